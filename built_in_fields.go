@@ -29,6 +29,26 @@ func newField(dest interface{}, sourcesLen int, callback func(sourceNum int) Bin
 	return f
 }
 
+// formattedField is a field with a custom format for its current value, e.g.
+// when the destination is a plain integer that represents a byte size.
+type formattedField struct {
+	*field
+	format func() string
+}
+
+func (ff *formattedField) currentValueString() string {
+	return ff.format()
+}
+
+// bindingName returns the name of the config value the binding is bound to,
+// e.g. a JSON property or a CLI flag name, so it can be used in errors.
+func bindingName(binding Binding) string {
+	if fromSource, ok := binding.(BindingFromSource); ok {
+		return fromSource.BoundName()
+	}
+	return "value"
+}
+
 type arrayHandler func(arrLength int, getElement func(int) LazySingleValueBinder) error
 
 func newArrayField(dest interface{}, sources []ArrayValueBinder, handler arrayHandler) Field {
@@ -47,6 +67,35 @@ func newArrayField(dest interface{}, sources []ArrayValueBinder, handler arrayHa
 	})
 }
 
+// newMapField binds every element of the map with the given bind function,
+// e.g. LazySingleValueBinder.BindIntValueTo.
+func newMapField[T any](
+	dest *map[string]T, sources []MapValueBinder, bind func(LazySingleValueBinder, *T) Binding,
+) Field {
+	return newField(dest, len(sources), func(sourceNum int) Binding {
+		source := sources[sourceNum]
+		var keys []string
+		var getElement func(string) LazySingleValueBinder
+		binding := source.BindMapValueTo(&keys, &getElement)
+		return wrapBinding(binding, func() error {
+			err := binding.Apply()
+			if err != nil {
+				return err
+			}
+			newMap := make(map[string]T, len(keys))
+			for _, key := range keys {
+				var val T
+				if err := bind(getElement(key), &val).Apply(); err != nil {
+					return err
+				}
+				newMap[key] = val
+			}
+			*dest = newMap
+			return nil
+		})
+	})
+}
+
 func NewStringField(dest *string, sources ...StringValueBinder) Field {
 	return newField(dest, len(sources), func(sourceNum int) Binding {
 		return sources[sourceNum].BindStringValueTo(dest)
@@ -70,3 +119,63 @@ func NewCustomField(dest interface{}, sources ...CustomValueBinder) Field {
 		return sources[sourceNum].BindValue()
 	})
 }
+
+func NewStringSliceField(dest *[]string, sources ...ArrayValueBinder) Field {
+	return newArrayField(dest, sources, func(arrLength int, getElement func(int) LazySingleValueBinder) error {
+		newArr := make([]string, arrLength)
+		for i := 0; i < arrLength; i++ {
+			if err := getElement(i).BindStringValueTo(&newArr[i]).Apply(); err != nil {
+				return err
+			}
+		}
+		*dest = newArr
+		return nil
+	})
+}
+
+func NewBoolSliceField(dest *[]bool, sources ...ArrayValueBinder) Field {
+	return newArrayField(dest, sources, func(arrLength int, getElement func(int) LazySingleValueBinder) error {
+		newArr := make([]bool, arrLength)
+		for i := 0; i < arrLength; i++ {
+			if err := getElement(i).BindBoolValueTo(&newArr[i]).Apply(); err != nil {
+				return err
+			}
+		}
+		*dest = newArr
+		return nil
+	})
+}
+
+// NewTextBasedSliceField can be used for slices of any type that implements
+// encoding.TextUnmarshaler, e.g. []net.IP. Since we can't create the slice
+// ourselves without reflection, newTypedSlice is called every time a source
+// has a value. It should allocate a new slice with the given length and return
+// a function that returns a pointer to the element with the given index and a
+// function that saves the new slice in the destination, for example:
+//
+//	croconf.NewTextBasedSliceField(
+//		&conf.Hosts,
+//		func(length int) (func(int) encoding.TextUnmarshaler, func()) {
+//			newArr := make([]net.IP, length)
+//			element := func(i int) encoding.TextUnmarshaler { return &newArr[i] }
+//			save := func() { conf.Hosts = newArr }
+//			return element, save
+//		},
+//		jsonSource.From("hosts"),
+//	)
+func NewTextBasedSliceField(
+	dest interface{},
+	newTypedSlice func(int) (element func(int) encoding.TextUnmarshaler, save func()),
+	sources ...ArrayValueBinder,
+) Field {
+	return newArrayField(dest, sources, func(arrLength int, getElement func(int) LazySingleValueBinder) error {
+		element, save := newTypedSlice(arrLength)
+		for i := 0; i < arrLength; i++ {
+			if err := getElement(i).BindTextBasedValueTo(element(i)).Apply(); err != nil {
+				return err
+			}
+		}
+		save()
+		return nil
+	})
+}
@@ -0,0 +1,20 @@
+package croconf
+
+import "time"
+
+// NewDurationField creates a field for time.Duration values. Besides the
+// normal Go duration strings like "1h30m", it also supports days, e.g. "2d" or
+// "1d12h". Numbers without any units are treated as milliseconds, use
+// NewDurationFieldWithUnit if they should be interpreted differently.
+func NewDurationField(dest *time.Duration, sources ...DurationValueBinder) Field {
+	return NewDurationFieldWithUnit(dest, time.Millisecond, sources...)
+}
+
+// NewDurationFieldWithUnit is the same as NewDurationField, but numbers
+// without any units are multiplied by bareNumberUnit, e.g. with time.Second
+// the value 30 means 30 seconds.
+func NewDurationFieldWithUnit(dest *time.Duration, bareNumberUnit time.Duration, sources ...DurationValueBinder) Field {
+	return newField(dest, len(sources), func(sourceNum int) Binding {
+		return sources[sourceNum].BindDurationValueTo(dest, bareNumberUnit)
+	})
+}
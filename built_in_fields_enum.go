@@ -0,0 +1,59 @@
+package croconf
+
+import (
+	"fmt"
+	"strings"
+)
+
+// enumField is a field that only accepts a specific set of values. They are
+// exposed through ManagedField.PossibleValues, e.g. for help texts.
+type enumField struct {
+	*field
+	possibleValues []string
+}
+
+func (ef *enumField) PossibleValues() []string {
+	return ef.possibleValues
+}
+
+// NewEnumField creates a string field that only accepts one of the allowed
+// values and returns an error for anything else.
+func NewEnumField(dest *string, allowed []string, sources ...StringValueBinder) Field {
+	return NewTypedEnumField(dest, allowed, sources...)
+}
+
+// NewTypedEnumField is the same as NewEnumField, but for custom string types,
+// e.g. typed constants like:
+//
+//	type Compression string
+//	const (
+//		CompressionNone Compression = "none"
+//		CompressionGzip Compression = "gzip"
+//	)
+func NewTypedEnumField[T ~string](dest *T, allowed []T, sources ...StringValueBinder) Field {
+	possibleValues := make([]string, len(allowed))
+	for i, v := range allowed {
+		possibleValues[i] = string(v)
+	}
+
+	f := newField(dest, len(sources), func(sourceNum int) Binding {
+		var val string
+		binding := sources[sourceNum].BindStringValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			for _, v := range allowed {
+				if string(v) == val {
+					*dest = v
+					return nil
+				}
+			}
+			return fmt.Errorf(
+				"invalid value %s for %s, expected one of %s",
+				val, bindingName(binding), strings.Join(possibleValues, ", "),
+			)
+		})
+	})
+	return &enumField{field: f, possibleValues: possibleValues}
+}
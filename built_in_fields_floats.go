@@ -0,0 +1,75 @@
+package croconf
+
+import "reflect"
+
+func floatValHelper(sources []FloatValueBinder, bitSize int, saveToDest func(float64)) func(sourceNum int) Binding {
+	return func(sourceNum int) Binding {
+		var val float64
+		binding := sources[sourceNum].BindFloatValueTo(&val)
+
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			if err := checkFloatBitsize(val, bitSize); err != nil {
+				return err
+			}
+			saveToDest(val)
+			return nil
+		})
+	}
+}
+
+func NewFloat32Field(dest *float32, sources ...FloatValueBinder) Field {
+	return newField(dest, len(sources), floatValHelper(sources, 32, func(val float64) {
+		*dest = float32(val) // this is safe, floatValHelper checks val against bitSize
+	}))
+}
+
+func NewFloat64Field(dest *float64, sources ...FloatValueBinder) Field {
+	return newField(dest, len(sources), func(sourceNum int) Binding {
+		return sources[sourceNum].BindFloatValueTo(dest)
+	})
+}
+
+func floatSliceHandler(newTypedSlice func(int) (add func(float64) error, save func())) arrayHandler {
+	return func(arrLength int, getElement func(int) LazySingleValueBinder) error {
+		add, save := newTypedSlice(arrLength)
+		for i := 0; i < arrLength; i++ {
+			var val float64
+			elBinding := getElement(i).BindFloatValueTo(&val)
+			if err := elBinding.Apply(); err != nil {
+				return err
+			}
+			if err := add(val); err != nil {
+				return err
+			}
+		}
+		save()
+		return nil
+	}
+}
+
+func newFloatSliceField[T Float](dest *[]T, sources []ArrayValueBinder) Field {
+	bitSize := reflect.TypeOf(dest).Elem().Elem().Bits()
+	return newArrayField(dest, sources, floatSliceHandler(func(arrLength int) (func(float64) error, func()) {
+		newArr := make([]T, 0, arrLength)
+		add := func(val float64) error {
+			if err := checkFloatBitsize(val, bitSize); err != nil {
+				return err
+			}
+			newArr = append(newArr, T(val)) // this is safe
+			return nil
+		}
+		save := func() { *dest = newArr }
+		return add, save
+	}))
+}
+
+func NewFloat32SliceField(dest *[]float32, sources ...ArrayValueBinder) Field {
+	return newFloatSliceField(dest, sources)
+}
+
+func NewFloat64SliceField(dest *[]float64, sources ...ArrayValueBinder) Field {
+	return newFloatSliceField(dest, sources)
+}
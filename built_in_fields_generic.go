@@ -0,0 +1,206 @@
+package croconf
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Signed is a constraint for all signed integer types, including custom types
+// that are based on them, e.g. `type VUs int32`.
+type Signed interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned is a constraint for all unsigned integer types, including custom
+// types that are based on them.
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Float is a constraint for all floating-point types, including custom types
+// that are based on them.
+type Float interface {
+	~float32 | ~float64
+}
+
+// Value is a constraint for all of the basic types that the generic field
+// constructors support, i.e. every numeric type, strings and bools.
+type Value interface {
+	Signed | Unsigned | Float | ~string | ~bool
+}
+
+// bindValue binds a single value to dest, with the *ValueBinder method that
+// corresponds to the kind of T. Bit sizes are checked just like they are in
+// the non-generic field constructors. It's only used for the elements of
+// NewSliceField, since they implement every *ValueBinder interface.
+func bindValue[T Value](source LazySingleValueBinder, dest *T) Binding { //nolint:funlen
+	destVal := reflect.ValueOf(dest).Elem()
+	switch destVal.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var val int64
+		binding := source.BindIntValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			if err := checkIntBitsize(val, destVal.Type().Bits()); err != nil {
+				return err
+			}
+			destVal.SetInt(val)
+			return nil
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var val uint64
+		binding := source.BindUintValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			if err := checkUintBitsize(val, destVal.Type().Bits()); err != nil {
+				return err
+			}
+			destVal.SetUint(val)
+			return nil
+		})
+	case reflect.Float32, reflect.Float64:
+		var val float64
+		binding := source.BindFloatValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			if err := checkFloatBitsize(val, destVal.Type().Bits()); err != nil {
+				return err
+			}
+			destVal.SetFloat(val)
+			return nil
+		})
+	case reflect.String:
+		var val string
+		binding := source.BindStringValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			destVal.SetString(val)
+			return nil
+		})
+	case reflect.Bool:
+		var val bool
+		binding := source.BindBoolValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			destVal.SetBool(val)
+			return nil
+		})
+	default:
+		// This should be impossible, the type constraints don't allow it
+		return NewCallbackBinding(func() error {
+			return fmt.Errorf("unsupported kind %s", destVal.Kind())
+		})
+	}
+}
+
+// formatValue returns the string representation of val that the parsing
+// *ValueBinder methods of defaultStringValue expect.
+func formatValue[T Value](val T) string {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, rv.Type().Bits())
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	default:
+		return rv.String()
+	}
+}
+
+// NewSignedField creates a field for any type with an underlying signed
+// integer kind, including custom types like `type VUs int32`, without having to
+// write a custom Field implementation for it. Bit sizes are checked just like
+// they are in NewInt32Field and the other non-generic constructors.
+func NewSignedField[T Signed](dest *T, sources ...IntValueBinder) Field {
+	return newIntField(dest, sources)
+}
+
+// NewUnsignedField is the same as NewSignedField, but for unsigned integer
+// types.
+func NewUnsignedField[T Unsigned](dest *T, sources ...UintValueBinder) Field {
+	return newUintField(dest, sources)
+}
+
+// NewFloatField is the same as NewSignedField, but for floating-point types.
+func NewFloatField[T Float](dest *T, sources ...FloatValueBinder) Field {
+	bitSize := reflect.TypeOf(dest).Elem().Bits()
+	return newField(dest, len(sources), floatValHelper(sources, bitSize, func(val float64) {
+		*dest = T(val) // this is safe, floatValHelper checks val against bitSize
+	}))
+}
+
+// NewTypedStringField is the same as NewStringField, but for custom string
+// types.
+func NewTypedStringField[T ~string](dest *T, sources ...StringValueBinder) Field {
+	return newField(dest, len(sources), func(sourceNum int) Binding {
+		var val string
+		binding := sources[sourceNum].BindStringValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			*dest = T(val)
+			return nil
+		})
+	})
+}
+
+// NewTypedBoolField is the same as NewBoolField, but for custom bool types.
+func NewTypedBoolField[T ~bool](dest *T, sources ...BoolValueBinder) Field {
+	return newField(dest, len(sources), func(sourceNum int) Binding {
+		var val bool
+		binding := sources[sourceNum].BindBoolValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			*dest = T(val)
+			return nil
+		})
+	})
+}
+
+// NewSliceField creates a slice field for any Value type, e.g. []VUs. Its
+// elements are bound with the *ValueBinder method for the kind of T.
+func NewSliceField[T Value](dest *[]T, sources ...ArrayValueBinder) Field {
+	return newArrayField(dest, sources, func(arrLength int, getElement func(int) LazySingleValueBinder) error {
+		newArr := make([]T, arrLength)
+		for i := 0; i < arrLength; i++ {
+			if err := bindValue(getElement(i), &newArr[i]).Apply(); err != nil {
+				return err
+			}
+		}
+		*dest = newArr
+		return nil
+	})
+}
+
+// DefaultValue is a type-safe default value for the generic field
+// constructors, e.g. DefaultValue[VUs](1).
+func DefaultValue[T Value](val T) LazySingleValueBinder {
+	return defaultStringValue(formatValue(val))
+}
+
+// DefaultSliceValue is a type-safe default value for NewSliceField.
+func DefaultSliceValue[T Value](values ...T) ArrayValueBinder {
+	res := make(defaultSliceValue, len(values))
+	for i, v := range values {
+		res[i] = formatValue(v)
+	}
+	return res
+}
@@ -1,6 +1,6 @@
-package croconf //nolint:dupl
+package croconf
 
-import "strconv"
+import "reflect"
 
 func intValHelper(sources []IntValueBinder, bitSize int, saveToDest func(int64)) func(sourceNum int) Binding {
 	return func(sourceNum int) Binding {
@@ -20,28 +20,27 @@ func intValHelper(sources []IntValueBinder, bitSize int, saveToDest func(int64))
 	}
 }
 
-func NewIntField(dest *int, sources ...IntValueBinder) Field {
-	return newField(dest, len(sources), intValHelper(sources, strconv.IntSize, func(val int64) {
-		*dest = int(val) // this is safe, intValHelper checks val against bitSize
+func newIntField[T Signed](dest *T, sources []IntValueBinder) Field {
+	bitSize := reflect.TypeOf(dest).Elem().Bits()
+	return newField(dest, len(sources), intValHelper(sources, bitSize, func(val int64) {
+		*dest = T(val) // this is safe, intValHelper checks val against bitSize
 	}))
 }
 
+func NewIntField(dest *int, sources ...IntValueBinder) Field {
+	return newIntField(dest, sources)
+}
+
 func NewInt8Field(dest *int8, sources ...IntValueBinder) Field {
-	return newField(dest, len(sources), intValHelper(sources, 8, func(val int64) {
-		*dest = int8(val) // this is safe, intValHelper checks val against bitSize
-	}))
+	return newIntField(dest, sources)
 }
 
 func NewInt16Field(dest *int16, sources ...IntValueBinder) Field {
-	return newField(dest, len(sources), intValHelper(sources, 16, func(val int64) {
-		*dest = int16(val) // this is safe, intValHelper checks val against bitSize
-	}))
+	return newIntField(dest, sources)
 }
 
 func NewInt32Field(dest *int32, sources ...IntValueBinder) Field {
-	return newField(dest, len(sources), intValHelper(sources, 32, func(val int64) {
-		*dest = int32(val) // this is safe, intValHelper checks val against bitSize
-	}))
+	return newIntField(dest, sources)
 }
 
 func NewInt64Field(dest *int64, sources ...IntValueBinder) Field {
@@ -68,14 +67,15 @@ func intSliceHandler(newTypedSlice func(int) (add func(int64) error, save func()
 	}
 }
 
-func NewIntSliceField(dest *[]int, sources ...ArrayValueBinder) Field {
+func newIntSliceField[T Signed](dest *[]T, sources []ArrayValueBinder) Field {
+	bitSize := reflect.TypeOf(dest).Elem().Elem().Bits()
 	return newArrayField(dest, sources, intSliceHandler(func(arrLength int) (func(int64) error, func()) {
-		newArr := make([]int, 0, arrLength)
+		newArr := make([]T, 0, arrLength)
 		add := func(val int64) error {
-			if err := checkIntBitsize(val, strconv.IntSize); err != nil {
+			if err := checkIntBitsize(val, bitSize); err != nil {
 				return err
 			}
-			newArr = append(newArr, int(val)) // this is safe
+			newArr = append(newArr, T(val)) // this is safe
 			return nil
 		}
 		save := func() { *dest = newArr }
@@ -83,59 +83,22 @@ func NewIntSliceField(dest *[]int, sources ...ArrayValueBinder) Field {
 	}))
 }
 
+func NewIntSliceField(dest *[]int, sources ...ArrayValueBinder) Field {
+	return newIntSliceField(dest, sources)
+}
+
 func NewInt8SliceField(dest *[]int8, sources ...ArrayValueBinder) Field {
-	return newArrayField(dest, sources, intSliceHandler(func(arrLength int) (func(int64) error, func()) {
-		newArr := make([]int8, 0, arrLength)
-		add := func(val int64) error {
-			if err := checkIntBitsize(val, 8); err != nil {
-				return err
-			}
-			newArr = append(newArr, int8(val)) // this is safe
-			return nil
-		}
-		save := func() { *dest = newArr }
-		return add, save
-	}))
+	return newIntSliceField(dest, sources)
 }
 
 func NewInt16SliceField(dest *[]int16, sources ...ArrayValueBinder) Field {
-	return newArrayField(dest, sources, intSliceHandler(func(arrLength int) (func(int64) error, func()) {
-		newArr := make([]int16, 0, arrLength)
-		add := func(val int64) error {
-			if err := checkIntBitsize(val, 16); err != nil {
-				return err
-			}
-			newArr = append(newArr, int16(val)) // this is safe
-			return nil
-		}
-		save := func() { *dest = newArr }
-		return add, save
-	}))
+	return newIntSliceField(dest, sources)
 }
 
 func NewInt32SliceField(dest *[]int32, sources ...ArrayValueBinder) Field {
-	return newArrayField(dest, sources, intSliceHandler(func(arrLength int) (func(int64) error, func()) {
-		newArr := make([]int32, 0, arrLength)
-		add := func(val int64) error {
-			if err := checkIntBitsize(val, 32); err != nil {
-				return err
-			}
-			newArr = append(newArr, int32(val)) // this is safe
-			return nil
-		}
-		save := func() { *dest = newArr }
-		return add, save
-	}))
+	return newIntSliceField(dest, sources)
 }
 
 func NewInt64SliceField(dest *[]int64, sources ...ArrayValueBinder) Field {
-	return newArrayField(dest, sources, intSliceHandler(func(arrLength int) (func(int64) error, func()) {
-		newArr := make([]int64, 0, arrLength)
-		add := func(val int64) error {
-			newArr = append(newArr, val)
-			return nil
-		}
-		save := func() { *dest = newArr }
-		return add, save
-	}))
+	return newIntSliceField(dest, sources)
 }
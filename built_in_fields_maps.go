@@ -0,0 +1,21 @@
+package croconf
+
+func NewStringMapField(dest *map[string]string, sources ...MapValueBinder) Field {
+	return newMapField(dest, sources, LazySingleValueBinder.BindStringValueTo)
+}
+
+func NewInt64MapField(dest *map[string]int64, sources ...MapValueBinder) Field {
+	return newMapField(dest, sources, LazySingleValueBinder.BindIntValueTo)
+}
+
+func NewUint64MapField(dest *map[string]uint64, sources ...MapValueBinder) Field {
+	return newMapField(dest, sources, LazySingleValueBinder.BindUintValueTo)
+}
+
+func NewFloat64MapField(dest *map[string]float64, sources ...MapValueBinder) Field {
+	return newMapField(dest, sources, LazySingleValueBinder.BindFloatValueTo)
+}
+
+func NewBoolMapField(dest *map[string]bool, sources ...MapValueBinder) Field {
+	return newMapField(dest, sources, LazySingleValueBinder.BindBoolValueTo)
+}
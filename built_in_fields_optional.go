@@ -0,0 +1,60 @@
+package croconf
+
+import "encoding"
+
+// newOptionalField binds every source to a temporary value and only allocates
+// the destination pointer when a binding was successfully applied. So, if none
+// of the sources had a value, the destination will remain nil.
+func newOptionalField[T any](dest **T, sourcesLen int, bind func(sourceNum int, val *T) Binding) Field {
+	return newField(dest, sourcesLen, func(sourceNum int) Binding {
+		val := new(T)
+		binding := bind(sourceNum, val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			newVal := *val // copy it, so different bindings don't share memory
+			*dest = &newVal
+			return nil
+		})
+	})
+}
+
+func NewOptionalInt64Field(dest **int64, sources ...IntValueBinder) Field {
+	return newOptionalField(dest, len(sources), func(sourceNum int, val *int64) Binding {
+		return sources[sourceNum].BindIntValueTo(val)
+	})
+}
+
+func NewOptionalFloat64Field(dest **float64, sources ...FloatValueBinder) Field {
+	return newOptionalField(dest, len(sources), func(sourceNum int, val *float64) Binding {
+		return sources[sourceNum].BindFloatValueTo(val)
+	})
+}
+
+func NewOptionalStringField(dest **string, sources ...StringValueBinder) Field {
+	return newOptionalField(dest, len(sources), func(sourceNum int, val *string) Binding {
+		return sources[sourceNum].BindStringValueTo(val)
+	})
+}
+
+func NewOptionalBoolField(dest **bool, sources ...BoolValueBinder) Field {
+	return newOptionalField(dest, len(sources), func(sourceNum int, val *bool) Binding {
+		return sources[sourceNum].BindBoolValueTo(val)
+	})
+}
+
+// TextUnmarshalerPointer is a constraint for pointers to types that implement
+// encoding.TextUnmarshaler with a pointer receiver, e.g. *net.IP.
+type TextUnmarshalerPointer[T any] interface {
+	*T
+	encoding.TextUnmarshaler
+}
+
+// NewOptionalTextBasedField is the optional equivalent of NewTextBasedField,
+// e.g. it can be used with a **net.IP destination.
+func NewOptionalTextBasedField[T any, PT TextUnmarshalerPointer[T]](dest **T, sources ...TextBasedValueBinder) Field {
+	return newOptionalField(dest, len(sources), func(sourceNum int, val *T) Binding {
+		return sources[sourceNum].BindTextBasedValueTo(PT(val))
+	})
+}
@@ -0,0 +1,109 @@
+package croconf
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"regexp/syntax"
+	"time"
+)
+
+// parsedStringHelper binds a string value and then parses it with the given
+// function, which can return a user-friendly description of what was wrong.
+func parsedStringHelper(
+	sources []StringValueBinder, valueKind string, parse func(string) error,
+) func(sourceNum int) Binding {
+	return func(sourceNum int) Binding {
+		var val string
+		binding := sources[sourceNum].BindStringValueTo(&val)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+			if err := parse(val); err != nil {
+				return fmt.Errorf("invalid %s '%s' for %s: %w", valueKind, val, bindingName(binding), err)
+			}
+			return nil
+		})
+	}
+}
+
+func NewIPField(dest *net.IP, sources ...StringValueBinder) Field {
+	return newField(dest, len(sources), parsedStringHelper(sources, "IP address", func(s string) error {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return errors.New("expected an IPv4 address like 192.0.2.1 or an IPv6 address like 2001:db8::1")
+		}
+		*dest = ip
+		return nil
+	}))
+}
+
+func NewIPNetField(dest *net.IPNet, sources ...StringValueBinder) Field {
+	return newField(dest, len(sources), parsedStringHelper(sources, "IP network", func(s string) error {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return errors.New("expected a network in the CIDR notation, like 192.0.2.0/24 or 2001:db8::/32")
+		}
+		*dest = *ipNet
+		return nil
+	}))
+}
+
+func NewURLField(dest **url.URL, sources ...StringValueBinder) Field {
+	return newField(dest, len(sources), parsedStringHelper(sources, "URL", func(s string) error {
+		u, err := url.Parse(s)
+		if err != nil {
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				return urlErr.Err // the rest of it just repeats the value
+			}
+			return err
+		}
+		*dest = u
+		return nil
+	}))
+}
+
+func NewRegexpField(dest **regexp.Regexp, sources ...StringValueBinder) Field {
+	return newField(dest, len(sources), parsedStringHelper(sources, "regular expression", func(s string) error {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			var syntaxErr *syntax.Error
+			if errors.As(err, &syntaxErr) {
+				return fmt.Errorf("%s in '%s'", syntaxErr.Code, syntaxErr.Expr)
+			}
+			return err
+		}
+		*dest = re
+		return nil
+	}))
+}
+
+// NewTimeField creates a field for time.Time values in the given layout, e.g.
+// time.RFC3339 or "2006-01-02". See the time package for details.
+func NewTimeField(dest *time.Time, layout string, sources ...StringValueBinder) Field {
+	return newField(dest, len(sources), parsedStringHelper(sources, "time", func(s string) error {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return fmt.Errorf("expected a value in the format %s", layout)
+		}
+		*dest = t
+		return nil
+	}))
+}
+
+// NewLocationField creates a field for time zones, e.g. "UTC", "Local" or IANA
+// Time Zone database names like "Europe/Sofia".
+func NewLocationField(dest **time.Location, sources ...StringValueBinder) Field {
+	return newField(dest, len(sources), parsedStringHelper(sources, "time zone", func(s string) error {
+		loc, err := time.LoadLocation(s)
+		if err != nil {
+			return errors.New("expected UTC, Local or a name from the IANA Time Zone database, like America/New_York")
+		}
+		*dest = loc
+		return nil
+	}))
+}
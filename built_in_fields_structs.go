@@ -0,0 +1,96 @@
+package croconf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NewStructSliceField can be used for slices of structs, e.g. arrays of
+// objects in a JSON config. For every element in the array, the element
+// callback is called with a pointer to the new element, a binder for the
+// properties of the element and a Manager. The callback should add fields for
+// the element properties to that Manager, the same way it would be done for
+// top-level fields, for example:
+//
+//	croconf.NewStructSliceField(
+//		&conf.Targets,
+//		func(target *Target, el croconf.ObjectValueBinder, cm *croconf.Manager) {
+//			cm.AddField(croconf.NewStringField(&target.URL, el.Property("url")), croconf.IsRequired())
+//		},
+//		jsonSource.From("targets"),
+//		envVarsSource.From("APP_TARGETS"),
+//	)
+func NewStructSliceField[T any](
+	dest *[]T,
+	element func(el *T, binder ObjectValueBinder, cm *Manager),
+	sources ...ObjectArrayValueBinder,
+) Field {
+	return newField(dest, len(sources), func(sourceNum int) Binding {
+		var arrLength int
+		var getElement func(int) ObjectValueBinder
+		binding := sources[sourceNum].BindObjectArrayValueTo(&arrLength, &getElement)
+		return wrapBinding(binding, func() error {
+			if err := binding.Apply(); err != nil {
+				return err
+			}
+
+			newArr := make([]T, arrLength)
+			var errs []error
+			for i := 0; i < arrLength; i++ {
+				cm := NewManager()
+				el := getElement(i)
+				element(&newArr[i], el, cm)
+				elementName := fmt.Sprintf("%s[%d]", bindingName(binding), i)
+				errs = append(errs, consolidateElement(cm, elementName, el)...)
+			}
+			if len(errs) > 0 {
+				return joinErrors(errs)
+			}
+
+			*dest = newArr
+			return nil
+		})
+	})
+}
+
+// namedObjectBinder is implemented by the object binders of the built-in
+// sources, so the fields of array elements can be named relative to them.
+type namedObjectBinder interface {
+	propertyPrefix() string
+}
+
+// consolidateElement consolidates and validates the fields of a single array
+// element. Unlike Manager.Consolidate(), it doesn't initialize any sources,
+// since the element properties come from the same sources as the array. All
+// errors are prefixed with the element name, e.g. targets[1].
+func consolidateElement(cm *Manager, elementName string, el ObjectValueBinder) []error {
+	if named, ok := el.(namedObjectBinder); ok {
+		for _, f := range cm.fields {
+			f.Name = strings.TrimPrefix(f.Name, named.propertyPrefix())
+		}
+	}
+
+	var errs []error
+	for _, f := range cm.fields {
+		errs = append(errs, f.Consolidate()...)
+	}
+	if len(errs) == 0 {
+		errs = cm.validateFields()
+	}
+	for i, err := range errs {
+		errs[i] = fmt.Errorf("%s: %w", elementName, err)
+	}
+	return errs
+}
+
+func joinErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
@@ -1,10 +1,16 @@
 package croconf
 
 import (
+	"encoding"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 type testCaseGroup struct {
@@ -27,6 +33,21 @@ type fieldTestCase struct {
 	expectedErrors []string
 }
 
+// Custom types for testing the generic field constructors
+type (
+	testVUs  int16
+	testMode string
+	testPort uint16
+	testRate float32
+	testFlag bool
+)
+
+type testTarget struct {
+	URL        string
+	MaxRetries int64
+	Tags       []string
+}
+
 var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 	{
 		name: "simple int64 field",
@@ -45,9 +66,8 @@ var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 				expectedValue: int64(1), // default, no sources
 			},
 			{
-				json: `{"vus": "foo"}`,
-				// TODO: improve this error message, something like `"foo" is not a valid integer value` would be much better
-				expectedErrors: []string{`BindIntValue: parsing "\"foo\"": invalid syntax`},
+				json:           `{"vus": "foo"}`,
+				expectedErrors: []string{`json:1:9: vus: expected an integer, got "foo"`},
 			},
 			{
 				json:          `{"vus": 2}`,
@@ -67,7 +87,7 @@ var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 				json: `{"vus": "foo"}`,
 				env:  []string{"K6_VUS=bar"},
 				expectedErrors: []string{ // TODO: better error messages
-					`BindIntValue: parsing "\"foo\"": invalid syntax`,
+					`json:1:9: vus: expected an integer, got "foo"`,
 					`BindIntValue: parsing "bar": invalid syntax`,
 				},
 			},
@@ -148,7 +168,7 @@ var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 			var dest bool
 			return NewBoolField(
 				&dest,
-				// DefaultBoolValue(true), // TODO
+				DefaultBoolValue(true),
 				sources.json.From("throw"),
 				sources.env.From("K6_THROW"),
 				sources.cli.FromName("throw"),
@@ -156,7 +176,7 @@ var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 		},
 		testCases: []fieldTestCase{
 			{
-				expectedValue: false,
+				expectedValue: true,
 			},
 			{
 				json:          `{"throw": false}`,
@@ -168,7 +188,7 @@ var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 			},
 			{
 				json:           `{"throw": 123}`,
-				expectedErrors: []string{`json: cannot unmarshal number into Go value of type bool`}, // TODO: better error
+				expectedErrors: []string{`json:1:11: throw: expected a boolean, got 123`},
 			},
 			{
 				json:          `{"throw": false}`,
@@ -228,13 +248,17 @@ var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 			var dest []int8
 			return NewInt8SliceField(
 				&dest,
+				DefaultIntSliceValue(-1, 0),
 				sources.json.From("tinyArr"),
 				sources.env.From("TINY_ARR"),
 				sources.cli.FromName("tiny-arr"),
 			)
 		},
 		testCases: []fieldTestCase{
-			// TODO: test defaults and null values
+			// TODO: test null values
+			{
+				expectedValue: []int8{-1, 0},
+			},
 			{
 				json:          `{"tinyArr": [1, 2]}`,
 				expectedValue: []int8{1, 2},
@@ -291,7 +315,7 @@ var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 				expectedErrors: []string{
 					// TODO: better errors
 					`BindIntValue: parsing "foo": invalid syntax`,
-					`BindIntValue: parsing "null": invalid syntax`,
+					`json:1:19: bigArr[2]: expected an integer, got null`,
 				},
 			},
 		},
@@ -313,61 +337,1096 @@ var testCaseGroups = []testCaseGroup{ //nolint:gochecknoglobals
 			// TODO: add more test cases for this field?
 		},
 	},
-	// TODO: add a lot more like these...
-}
-
-func runTestCase(t *testing.T, tcg testCaseGroup, tc fieldTestCase) {
-	// TODO: actually test source failures as well?
-	sources := testSources{
-		json: NewJSONSource([]byte(tc.json)),
-		env:  NewSourceFromEnv(tc.env),
-		cli:  NewSourceFromCLIFlags(tc.cli),
-	}
-
-	field := tcg.field(sources)
-
-	for _, s := range []Source{sources.json, sources.env, sources.cli} {
-		if err := s.Initialize(); err != nil {
-			t.Fatalf("unexpected error when initializing %s source: %s", s.GetName(), err)
-		}
-	}
-
-	mf := &ManagedField{Field: field}
-	errs := mf.Consolidate()
-	if len(tc.expectedErrors) != len(errs) {
-		t.Fatalf("Expected %d errors but got %d: %#v", len(tc.expectedErrors), len(errs), errs)
-	}
-
-	for i, expErr := range tc.expectedErrors {
-		if !strings.Contains(errs[i].Error(), expErr) {
-			t.Errorf("Expected error #%d to contain '%s' but it is '%s'", i, expErr, errs[i].Error())
-		}
-	}
-	if len(tc.expectedErrors) == 0 {
-		destPointer := field.Destination()
-		value := reflect.Indirect(reflect.ValueOf(destPointer)).Interface()
-
-		if !reflect.DeepEqual(tc.expectedValue, value) {
-			t.Errorf(
-				"Expected to get value '%#v' (%T), but got '%#v' (%T)",
-				tc.expectedValue, tc.expectedValue, value, value)
-		}
-	}
-}
-
-func TestBuiltInFileds(t *testing.T) {
-	t.Parallel()
-	for i, tcg := range testCaseGroups {
-		i, tcg := i, tcg
-		t.Run(fmt.Sprintf("%03d: %s", i, tcg.name), func(t *testing.T) {
-			t.Parallel()
-			for j, tc := range tcg.testCases {
-				j, tc := j, tc
-				t.Run(fmt.Sprintf("TC#%03d", j), func(t *testing.T) {
-					t.Parallel()
-					runTestCase(t, tcg, tc)
-				})
-			}
-		})
+	{
+		name: "float32 field",
+		field: func(sources testSources) Field {
+			var dest float32
+			return NewFloat32Field(
+				&dest,
+				DefaultFloatValue(0.5),
+				sources.json.From("rate"),
+				sources.env.From("K6_RATE"),
+				sources.cli.FromName("rate"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: float32(0.5),
+			},
+			{
+				json:          `{"rate": 1.25}`,
+				expectedValue: float32(1.25),
+			},
+			{
+				json:          `{"rate": 1.25}`,
+				env:           []string{"K6_RATE=-3"},
+				expectedValue: float32(-3),
+			},
+			{
+				cli:            []string{"--rate=1e39"},
+				expectedErrors: []string{`invalid value 1e+39, it must be between -3.4028234663852886e+38 and 3.4028234663852886e+38`},
+			},
+			{
+				env:            []string{"K6_RATE=1e-50"},
+				expectedErrors: []string{`invalid value 1e-50, it is too close to 0 and would be rounded to 0 as a 32-bit float`},
+			},
+		},
+	},
+	{
+		name: "float64 array",
+		field: func(sources testSources) Field {
+			var dest []float64
+			return NewFloat64SliceField(
+				&dest,
+				sources.json.From("thresholds"),
+				sources.env.From("K6_THRESHOLDS"),
+				sources.cli.FromName("threshold"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				json:          `{"thresholds": [0.1, 2, 1e300]}`,
+				expectedValue: []float64{0.1, 2, 1e300},
+			},
+			{
+				json:          `{"thresholds": [0.1]}`,
+				env:           []string{`K6_THRESHOLDS=0.5,0.75`},
+				expectedValue: []float64{0.5, 0.75},
+			},
+			{
+				env:           []string{`K6_THRESHOLDS=0.5,0.75`},
+				cli:           []string{"--threshold", "0.99", "--threshold=0.999"},
+				expectedValue: []float64{0.99, 0.999},
+			},
+			{
+				env:            []string{`K6_THRESHOLDS=0.5,foo`},
+				expectedErrors: []string{`BindFloatValue: parsing "foo": invalid syntax`},
+			},
+		},
+	},
+	{
+		name: "float32 array",
+		field: func(sources testSources) Field {
+			var dest []float32
+			return NewFloat32SliceField(&dest, sources.json.From("arr"))
+		},
+		testCases: []fieldTestCase{
+			{
+				json:          `{"arr": [0.5, -2]}`,
+				expectedValue: []float32{0.5, -2},
+			},
+			{
+				json:           `{"arr": [0.5, 1e40]}`,
+				expectedErrors: []string{`invalid value 1e+40, it must be between`},
+			},
+		},
+	},
+	{
+		name: "string array",
+		field: func(sources testSources) Field {
+			var dest []string
+			return NewStringSliceField(
+				&dest,
+				sources.json.From("tags"),
+				sources.env.From("K6_TAGS"),
+				sources.cli.FromNameAndShorthand("tag", "t"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: []string(nil),
+			},
+			{
+				json:          `{"tags": ["foo", "bar"]}`,
+				expectedValue: []string{"foo", "bar"},
+			},
+			{
+				json:          `{"tags": ["foo", "bar"]}`,
+				env:           []string{"K6_TAGS=baz"},
+				expectedValue: []string{"baz"},
+			},
+			{
+				env:           []string{"K6_TAGS=baz"},
+				cli:           []string{"--tag", "a", "-t", "b"},
+				expectedValue: []string{"a", "b"},
+			},
+			{
+				json:           `{"tags": ["foo", 1]}`,
+				expectedErrors: []string{`json:1:18: tags[1]: expected a string, got 1`},
+			},
+		},
+	},
+	{
+		name: "bool array",
+		field: func(sources testSources) Field {
+			var dest []bool
+			return NewBoolSliceField(
+				&dest,
+				sources.json.From("flags"),
+				sources.env.From("FLAGS"),
+				sources.cli.FromName("flag"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				json:          `{"flags": [true, false]}`,
+				expectedValue: []bool{true, false},
+			},
+			{
+				env:           []string{"FLAGS=1,false,TRUE"},
+				expectedValue: []bool{true, false, true},
+			},
+			{
+				cli:           []string{"--flag", "false", "--flag=true"},
+				expectedValue: []bool{false, true},
+			},
+		},
+	},
+	{
+		name: "text-based array",
+		field: func(sources testSources) Field {
+			var dest []net.IP
+			return NewTextBasedSliceField(
+				&dest,
+				func(length int) (func(int) encoding.TextUnmarshaler, func()) {
+					newArr := make([]net.IP, length)
+					element := func(i int) encoding.TextUnmarshaler { return &newArr[i] }
+					save := func() { dest = newArr }
+					return element, save
+				},
+				sources.json.From("hosts"),
+				sources.env.From("HOSTS"),
+				sources.cli.FromName("host"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				json:          `{"hosts": ["127.0.0.1", "::1"]}`,
+				expectedValue: []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+			},
+			{
+				json:          `{"hosts": ["127.0.0.1", "::1"]}`,
+				env:           []string{"HOSTS=10.0.0.1,10.0.0.2"},
+				expectedValue: []net.IP{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")},
+			},
+			{
+				cli:           []string{"--host", "1.1.1.1"},
+				expectedValue: []net.IP{net.ParseIP("1.1.1.1")},
+			},
+			{
+				env:            []string{"HOSTS=10.0.0.1,foo"},
+				expectedErrors: []string{`invalid IP address: foo`},
+			},
+		},
+	},
+	{
+		name: "string map",
+		field: func(sources testSources) Field {
+			var dest map[string]string
+			return NewStringMapField(
+				&dest,
+				sources.json.From("tags"),
+				sources.env.From("K6_TAGS"),
+				sources.cli.FromName("tag"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: map[string]string(nil),
+			},
+			{
+				json:          `{"tags": {"foo": "bar", "baz": ""}}`,
+				expectedValue: map[string]string{"foo": "bar", "baz": ""},
+			},
+			{
+				json:          `{"tags": {"foo": "bar"}}`,
+				env:           []string{"K6_TAGS=a=1,b=c=d"},
+				expectedValue: map[string]string{"a": "1", "b": "c=d"},
+			},
+			{
+				env:           []string{"K6_TAGS=a=1"},
+				cli:           []string{"--tag", "x=1", "--tag=y=2", "--tag", "x=3"},
+				expectedValue: map[string]string{"x": "3", "y": "2"},
+			},
+			{
+				env:            []string{"K6_TAGS=a=1,b"},
+				expectedErrors: []string{`invalid value 'b' for K6_TAGS, expected a key=value pair`},
+			},
+			{
+				json:           `{"tags": ["foo"]}`,
+				expectedErrors: []string{`json:1:10: tags: expected an object, got an array`},
+			},
+		},
+	},
+	{
+		name: "int64 map",
+		field: func(sources testSources) Field {
+			var dest map[string]int64
+			return NewInt64MapField(
+				&dest,
+				sources.json.From("limits"),
+				sources.env.From("LIMITS"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				json:          `{"limits": {"foo": 1, "bar": -2}}`,
+				expectedValue: map[string]int64{"foo": 1, "bar": -2},
+			},
+			{
+				env:           []string{"LIMITS=foo=3"},
+				expectedValue: map[string]int64{"foo": 3},
+			},
+			{
+				env:            []string{"LIMITS=foo=bar"},
+				expectedErrors: []string{`BindIntValue: parsing "bar": invalid syntax`},
+			},
+		},
+	},
+	{
+		name: "string default for other types",
+		field: func(sources testSources) Field {
+			var dest uint16
+			return NewUint16Field(
+				&dest,
+				DefaultStringValue("8080"),
+				sources.env.From("PORT"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: uint16(8080),
+			},
+			{
+				env:           []string{"PORT=80"},
+				expectedValue: uint16(80),
+			},
+		},
+	},
+	{
+		name: "invalid string default",
+		field: func(sources testSources) Field {
+			var dest bool
+			return NewBoolField(&dest, DefaultStringValue("nope"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedErrors: []string{`BindBoolValue: parsing "nope": invalid syntax`},
+			},
+		},
+	},
+	{
+		name: "slice defaults",
+		field: func(sources testSources) Field {
+			var dest []string
+			return NewStringSliceField(
+				&dest,
+				DefaultStringSliceValue("foo", "bar"),
+				sources.env.From("LIST"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: []string{"foo", "bar"},
+			},
+			{
+				env:           []string{"LIST=baz"},
+				expectedValue: []string{"baz"},
+			},
+		},
+	},
+	{
+		name: "float slice defaults",
+		field: func(sources testSources) Field {
+			var dest []float32
+			return NewFloat32SliceField(&dest, DefaultFloatSliceValue(0.25, 1e-3))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: []float32{0.25, 1e-3},
+			},
+		},
+	},
+	{
+		name: "map defaults",
+		field: func(sources testSources) Field {
+			var dest map[string]string
+			return NewStringMapField(
+				&dest,
+				DefaultStringMapValue(map[string]string{"foo": "bar"}),
+				sources.json.From("tags"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: map[string]string{"foo": "bar"},
+			},
+			{
+				json:          `{"tags": {}}`,
+				expectedValue: map[string]string{},
+			},
+		},
+	},
+	{
+		name: "generic signed field with a custom type",
+		field: func(sources testSources) Field {
+			var dest testVUs
+			return NewSignedField(
+				&dest,
+				DefaultValue[testVUs](1),
+				sources.json.From("vus"),
+				sources.env.From("K6_VUS"),
+				sources.cli.FromNameAndShorthand("vus", "u"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: testVUs(1),
+			},
+			{
+				json:          `{"vus": 2}`,
+				cli:           []string{"-u", "3"},
+				expectedValue: testVUs(3),
+			},
+			{
+				env:            []string{"K6_VUS=32768"},
+				expectedErrors: []string{`invalid value 32768, it must be between -32768 and 32767`},
+			},
+		},
+	},
+	{
+		name: "generic string field",
+		field: func(sources testSources) Field {
+			var dest testMode
+			return NewTypedStringField(&dest, DefaultValue[testMode]("fast"), sources.env.From("MODE"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: testMode("fast"),
+			},
+			{
+				env:           []string{"MODE=slow"},
+				expectedValue: testMode("slow"),
+			},
+		},
+	},
+	{
+		name: "generic slice field",
+		field: func(sources testSources) Field {
+			var dest []testPort
+			return NewSliceField(
+				&dest,
+				DefaultSliceValue[testPort](80, 443),
+				sources.json.From("ports"),
+				sources.env.From("PORTS"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: []testPort{80, 443},
+			},
+			{
+				json:          `{"ports": [8080]}`,
+				expectedValue: []testPort{8080},
+			},
+			{
+				env:            []string{"PORTS=1,65536"},
+				expectedErrors: []string{`invalid value 65536, it must be between 0 and 65535`},
+			},
+		},
+	},
+	{
+		name: "generic float field",
+		field: func(sources testSources) Field {
+			var dest float32
+			return NewFloatField(&dest, DefaultValue[float32](0.1), sources.json.From("rate"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: float32(0.1),
+			},
+			{
+				json:          `{"rate": 2.5}`,
+				expectedValue: float32(2.5),
+			},
+		},
+	},
+	{
+		name: "generic signed field with a narrow default",
+		field: func(sources testSources) Field {
+			var dest testVUs
+			return NewSignedField(&dest, DefaultIntValue(1), sources.env.From("K6_VUS"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: testVUs(1),
+			},
+			{
+				env:            []string{"K6_VUS=-32769"},
+				expectedErrors: []string{`invalid value -32769, it must be between -32768 and 32767`},
+			},
+		},
+	},
+	{
+		name: "generic unsigned field with a narrow default",
+		field: func(sources testSources) Field {
+			var dest testPort
+			return NewUnsignedField(&dest, DefaultUintValue(80), sources.json.From("port"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: testPort(80),
+			},
+			{
+				json:           `{"port": 65536}`,
+				expectedErrors: []string{`invalid value 65536, it must be between 0 and 65535`},
+			},
+		},
+	},
+	{
+		name: "generic float field with a narrow default",
+		field: func(sources testSources) Field {
+			var dest testRate
+			return NewFloatField(&dest, DefaultFloatValue(0.5), sources.cli.FromName("rate"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: testRate(0.5),
+			},
+			{
+				cli:           []string{"--rate", "1.5"},
+				expectedValue: testRate(1.5),
+			},
+		},
+	},
+	{
+		name: "generic bool field with a narrow default",
+		field: func(sources testSources) Field {
+			var dest testFlag
+			return NewTypedBoolField(&dest, DefaultBoolValue(true), sources.json.From("throw"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: testFlag(true),
+			},
+			{
+				json:          `{"throw": false}`,
+				expectedValue: testFlag(false),
+			},
+		},
+	},
+	{
+		name: "optional int64 field",
+		field: func(sources testSources) Field {
+			var dest *int64
+			return NewOptionalInt64Field(
+				&dest,
+				sources.json.From("vus"),
+				sources.env.From("K6_VUS"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: (*int64)(nil),
+			},
+			{
+				json:          `{"vus": 0}`,
+				expectedValue: func() *int64 { v := int64(0); return &v }(),
+			},
+			{
+				json:           `{"vus": 1}`,
+				env:            []string{"K6_VUS=foo"},
+				expectedErrors: []string{`BindIntValue: parsing "foo": invalid syntax`},
+			},
+		},
+	},
+	{
+		name: "optional string field with a default",
+		field: func(sources testSources) Field {
+			var dest *string
+			return NewOptionalStringField(
+				&dest,
+				DefaultStringValue(""),
+				sources.env.From("NAME"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: func() *string { v := ""; return &v }(),
+			},
+			{
+				env:           []string{"NAME=foo"},
+				expectedValue: func() *string { v := "foo"; return &v }(),
+			},
+		},
+	},
+	{
+		name: "optional text-based field",
+		field: func(sources testSources) Field {
+			var dest *net.IP
+			return NewOptionalTextBasedField(&dest, sources.cli.FromName("ip"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: (*net.IP)(nil),
+			},
+			{
+				cli:           []string{"--ip", "127.0.0.1"},
+				expectedValue: func() *net.IP { v := net.ParseIP("127.0.0.1"); return &v }(),
+			},
+		},
+	},
+	{
+		name: "struct slice field",
+		field: func(sources testSources) Field {
+			var dest []testTarget
+			return NewStructSliceField(
+				&dest,
+				func(target *testTarget, el ObjectValueBinder, cm *Manager) {
+					cm.AddField(NewStringField(&target.URL, el.Property("url")), IsRequired())
+					cm.AddField(NewInt64Field(&target.MaxRetries, DefaultIntValue(3), el.Property("maxRetries")))
+					cm.AddField(NewStringSliceField(&target.Tags, el.Property("tags")))
+				},
+				sources.json.From("targets"),
+				sources.env.From("APP_TARGETS"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: []testTarget(nil),
+			},
+			{
+				json: `{"targets": [{"url": "a", "tags": ["x"]}, {"url": "b", "maxRetries": 1}]}`,
+				expectedValue: []testTarget{
+					{URL: "a", MaxRetries: 3, Tags: []string{"x"}},
+					{URL: "b", MaxRetries: 1},
+				},
+			},
+			{
+				json: `{"targets": [{"url": "a"}]}`,
+				env: []string{
+					"APP_TARGETS_0_URL=c", "APP_TARGETS_0_TAGS=y,z",
+					"APP_TARGETS_1_URL=d", "APP_TARGETS_1_MAX_RETRIES=0",
+					"APP_TARGETS_3_URL=ignored, since there is no element #2",
+				},
+				expectedValue: []testTarget{
+					{URL: "c", MaxRetries: 3, Tags: []string{"y", "z"}},
+					{URL: "d", MaxRetries: 0},
+				},
+			},
+			{
+				json: `{"targets": [{"url": "a"}, {"url": "b"}, {"maxRetries": "foo"}]}`,
+				expectedErrors: []string{
+					`targets[2]: json:1:57: targets[2].maxRetries: expected an integer, got "foo"`,
+				},
+			},
+			{
+				json: `{"targets": [{"url": "a"}, {"url": "b"}, {"maxRetries": 5}]}`,
+				expectedErrors: []string{
+					`targets[2]: Field url is required, but no value was set`,
+				},
+			},
+			{
+				env:            []string{"APP_TARGETS_0_MAX_RETRIES=1"},
+				expectedErrors: []string{`APP_TARGETS[0]: Field URL is required, but no value was set`},
+			},
+		},
+	},
+	{
+		name: "duration field",
+		field: func(sources testSources) Field {
+			var dest time.Duration
+			return NewDurationField(
+				&dest,
+				DefaultStringValue("1m"),
+				sources.json.From("duration"),
+				sources.env.From("K6_DURATION"),
+				sources.cli.FromNameAndShorthand("duration", "d"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: time.Minute,
+			},
+			{
+				json:          `{"duration": "1d12h"}`,
+				expectedValue: 36 * time.Hour,
+			},
+			{
+				json:          `{"duration": 1500}`,
+				expectedValue: 1500 * time.Millisecond,
+			},
+			{
+				json:          `{"duration": "2d"}`,
+				env:           []string{"K6_DURATION=-1d6h"},
+				expectedValue: -30 * time.Hour,
+			},
+			{
+				cli:           []string{"-d", "10.5"},
+				expectedValue: 10500 * time.Microsecond,
+			},
+			{
+				json:           `{"duration": true}`,
+				expectedErrors: []string{`json:1:14: duration: expected a string or a number, got true`},
+			},
+			{
+				env:            []string{"K6_DURATION=1x"},
+				expectedErrors: []string{`BindDurationValue: parsing "1x": invalid duration`},
+			},
+			{
+				env:            []string{"K6_DURATION=1d-2h"},
+				expectedErrors: []string{`BindDurationValue: parsing "1d-2h": invalid duration after the days`},
+			},
+			{
+				cli:            []string{"--duration=1e30"},
+				expectedErrors: []string{`BindDurationValue: parsing "1e30": value out of range`},
+			},
+		},
+	},
+	{
+		name: "duration field with seconds",
+		field: func(sources testSources) Field {
+			var dest time.Duration
+			return NewDurationFieldWithUnit(
+				&dest, time.Second,
+				DefaultDurationValue(time.Hour),
+				sources.json.From("ttl"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: time.Hour,
+			},
+			{
+				json:          `{"ttl": 90}`,
+				expectedValue: 90 * time.Second,
+			},
+			{
+				json:          `{"ttl": "90"}`,
+				expectedValue: 90 * time.Second,
+			},
+		},
+	},
+	{
+		name: "IP field",
+		field: func(sources testSources) Field {
+			var dest net.IP
+			return NewIPField(&dest, DefaultStringValue("8.8.8.8"), sources.env.From("DNS_SERVER"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: net.ParseIP("8.8.8.8"),
+			},
+			{
+				env:            []string{"DNS_SERVER=1.2.3"},
+				expectedErrors: []string{`invalid IP address '1.2.3' for DNS_SERVER: expected an IPv4 address`},
+			},
+		},
+	},
+	{
+		name: "IP network field",
+		field: func(sources testSources) Field {
+			var dest net.IPNet
+			return NewIPNetField(&dest, sources.json.From("blacklist"))
+		},
+		testCases: []fieldTestCase{
+			{
+				json:          `{"blacklist": "10.0.0.0/8"}`,
+				expectedValue: net.IPNet{IP: net.IP{10, 0, 0, 0}, Mask: net.CIDRMask(8, 32)},
+			},
+			{
+				json:           `{"blacklist": "10.0.0.0"}`,
+				expectedErrors: []string{`invalid IP network '10.0.0.0' for blacklist: expected a network in the CIDR notation`},
+			},
+		},
+	},
+	{
+		name: "URL field",
+		field: func(sources testSources) Field {
+			var dest *url.URL
+			return NewURLField(&dest, sources.cli.FromName("url"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: (*url.URL)(nil),
+			},
+			{
+				cli:           []string{"--url", "https://k6.io/docs?foo=bar"},
+				expectedValue: &url.URL{Scheme: "https", Host: "k6.io", Path: "/docs", RawQuery: "foo=bar"},
+			},
+			{
+				cli:            []string{"--url", "://k6.io"},
+				expectedErrors: []string{`invalid URL '://k6.io' for --url: missing protocol scheme`},
+			},
+		},
+	},
+	{
+		name: "regexp field",
+		field: func(sources testSources) Field {
+			var dest *regexp.Regexp
+			return NewRegexpField(&dest, sources.env.From("FILTER"))
+		},
+		testCases: []fieldTestCase{
+			{
+				env:           []string{"FILTER=^a+$"},
+				expectedValue: regexp.MustCompile("^a+$"),
+			},
+			{
+				env:            []string{"FILTER=(a"},
+				expectedErrors: []string{`invalid regular expression '(a' for FILTER: missing closing ) in '(a'`},
+			},
+		},
+	},
+	{
+		name: "time field",
+		field: func(sources testSources) Field {
+			var dest time.Time
+			return NewTimeField(&dest, "2006-01-02", sources.json.From("since"))
+		},
+		testCases: []fieldTestCase{
+			{
+				json:          `{"since": "2021-07-01"}`,
+				expectedValue: time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC),
+			},
+			{
+				json:           `{"since": "01.07.2021"}`,
+				expectedErrors: []string{`invalid time '01.07.2021' for since: expected a value in the format 2006-01-02`},
+			},
+		},
+	},
+	{
+		name: "location field",
+		field: func(sources testSources) Field {
+			var dest *time.Location
+			return NewLocationField(&dest, DefaultStringValue("UTC"), sources.env.From("TZ"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: time.UTC,
+			},
+			{
+				env:            []string{"TZ=Mars/Olympus_Mons"},
+				expectedErrors: []string{`invalid time zone 'Mars/Olympus_Mons' for TZ: expected UTC, Local or a name`},
+			},
+		},
+	},
+	{
+		name: "byte size field",
+		field: func(sources testSources) Field {
+			var dest uint32
+			return NewByteSizeField(
+				&dest,
+				DefaultStringValue("1MiB"),
+				sources.json.From("maxSize"),
+				sources.env.From("MAX_SIZE"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: uint32(1 << 20),
+			},
+			{
+				json:          `{"maxSize": "10MB"}`,
+				expectedValue: uint32(10000000),
+			},
+			{
+				json:          `{"maxSize": "10MB"}`,
+				env:           []string{"MAX_SIZE=1.5 KiB"},
+				expectedValue: uint32(1536),
+			},
+			{
+				env:            []string{"MAX_SIZE=4GiB"},
+				expectedErrors: []string{`invalid byte size '4GiB' for MAX_SIZE: invalid value 4294967296, it must be between 0 and 4294967295`},
+			},
+			{
+				env:            []string{"MAX_SIZE=4XB"},
+				expectedErrors: []string{`invalid byte size '4XB' for MAX_SIZE: unknown unit prefix 'X'`},
+			},
+			{
+				env:           []string{"MAX_SIZE=0.1kB"},
+				expectedValue: uint32(100),
+			},
+			{
+				env:            []string{"MAX_SIZE=1.0001kB"},
+				expectedErrors: []string{`invalid byte size '1.0001kB' for MAX_SIZE: expected a whole number`},
+			},
+		},
+	},
+	{
+		name: "rate field",
+		field: func(sources testSources) Field {
+			var dest Rate
+			return NewRateField(&dest, DefaultStringValue("10/s"), sources.cli.FromName("rate"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: Rate{Amount: 10, Period: time.Second},
+			},
+			{
+				cli:           []string{"--rate", "5/1m"},
+				expectedValue: Rate{Amount: 5, Period: time.Minute},
+			},
+			{
+				cli:           []string{"--rate", "1.5k/2h"},
+				expectedValue: Rate{Amount: 1500, Period: 2 * time.Hour},
+			},
+			{
+				cli:            []string{"--rate", "5/0s"},
+				expectedErrors: []string{`invalid rate '5/0s' for --rate: invalid period '0s'`},
+			},
+		},
+	},
+	{
+		name: "enum field",
+		field: func(sources testSources) Field {
+			var dest string
+			return NewEnumField(
+				&dest, []string{"none", "gzip", "zstd"},
+				DefaultStringValue("none"),
+				sources.json.From("compression"),
+				sources.cli.FromName("compression"),
+			)
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: "none",
+			},
+			{
+				json:          `{"compression": "gzip"}`,
+				expectedValue: "gzip",
+			},
+			{
+				json:           `{"compression": "gzip"}`,
+				cli:            []string{"--compression=br"},
+				expectedErrors: []string{`invalid value br for --compression, expected one of none, gzip, zstd`},
+			},
+		},
+	},
+	{
+		name: "typed enum field",
+		field: func(sources testSources) Field {
+			var dest testMode
+			return NewTypedEnumField(&dest, []testMode{"fast", "slow"}, sources.env.From("MODE"))
+		},
+		testCases: []fieldTestCase{
+			{
+				expectedValue: testMode(""),
+			},
+			{
+				env:           []string{"MODE=slow"},
+				expectedValue: testMode("slow"),
+			},
+			{
+				env:            []string{"MODE=medium"},
+				expectedErrors: []string{`invalid value medium for MODE, expected one of fast, slow`},
+			},
+		},
+	},
+	// TODO: add a lot more like these...
+}
+
+func runTestCase(t *testing.T, tcg testCaseGroup, tc fieldTestCase) {
+	// TODO: actually test source failures as well?
+	sources := testSources{
+		json: NewJSONSource([]byte(tc.json)),
+		env:  NewSourceFromEnv(tc.env),
+		cli:  NewSourceFromCLIFlags(tc.cli),
+	}
+
+	field := tcg.field(sources)
+
+	for _, s := range []Source{sources.json, sources.env, sources.cli} {
+		if err := s.Initialize(); err != nil {
+			t.Fatalf("unexpected error when initializing %s source: %s", s.GetName(), err)
+		}
+	}
+
+	mf := &ManagedField{Field: field}
+	errs := mf.Consolidate()
+	if len(tc.expectedErrors) != len(errs) {
+		t.Fatalf("Expected %d errors but got %d: %#v", len(tc.expectedErrors), len(errs), errs)
+	}
+
+	for i, expErr := range tc.expectedErrors {
+		if !strings.Contains(errs[i].Error(), expErr) {
+			t.Errorf("Expected error #%d to contain '%s' but it is '%s'", i, expErr, errs[i].Error())
+		}
+	}
+	if len(tc.expectedErrors) == 0 {
+		destPointer := field.Destination()
+		value := reflect.Indirect(reflect.ValueOf(destPointer)).Interface()
+
+		if !reflect.DeepEqual(tc.expectedValue, value) {
+			t.Errorf(
+				"Expected to get value '%#v' (%T), but got '%#v' (%T)",
+				tc.expectedValue, tc.expectedValue, value, value)
+		}
+	}
+}
+
+func TestBuiltInFileds(t *testing.T) {
+	t.Parallel()
+	for i, tcg := range testCaseGroups {
+		i, tcg := i, tcg
+		t.Run(fmt.Sprintf("%03d: %s", i, tcg.name), func(t *testing.T) {
+			t.Parallel()
+			for j, tc := range tcg.testCases {
+				j, tc := j, tc
+				t.Run(fmt.Sprintf("TC#%03d", j), func(t *testing.T) {
+					t.Parallel()
+					runTestCase(t, tcg, tc)
+				})
+			}
+		})
+	}
+}
+
+func TestOptionalFieldDefaultValue(t *testing.T) {
+	t.Parallel()
+	var unset, set *bool
+	unsetField := &ManagedField{Field: NewOptionalBoolField(&unset)}
+	setField := &ManagedField{Field: NewOptionalBoolField(&set, DefaultBoolValue(true))}
+
+	if errs := unsetField.Consolidate(); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if errs := setField.Consolidate(); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	if unset != nil || unsetField.DefaultValue != "" {
+		t.Errorf("expected a nil value and an empty default, got %v and '%s'", unset, unsetField.DefaultValue)
+	}
+	if set == nil || !*set || setField.DefaultValue != "true" {
+		t.Errorf("expected a true value and default, got %v and '%s'", set, setField.DefaultValue)
+	}
+}
+
+func TestUnitsRoundTrip(t *testing.T) {
+	t.Parallel()
+	byteSizes := map[string]ByteSize{
+		"0B": 0, "1B": 1, "1kB": 1000, "1KiB": 1024, "10MB": 10e6, "512KiB": 512 << 10,
+		"1001B": 1001, "2000KiB": 2048000, "16EiB": 0, "15EiB": 15 << 60,
+	}
+	for str, val := range byteSizes {
+		if str == "16EiB" {
+			if _, err := ParseByteSize(str); err == nil {
+				t.Errorf("expected an error when parsing %s", str)
+			}
+			continue
+		}
+		parsed, err := ParseByteSize(str)
+		if err != nil || parsed != val || val.String() != str {
+			t.Errorf("expected %s to be %d, got %d (%v) and '%s'", str, val, parsed, err, val.String())
+		}
+	}
+
+	rates := map[string]Rate{
+		"100/s":    {Amount: 100, Period: time.Second},
+		"5/m":      {Amount: 5, Period: time.Minute},
+		"1Mi/ms":   {Amount: 1 << 20, Period: time.Millisecond},
+		"3/1m30s":  {Amount: 3, Period: 90 * time.Second},
+		"1k/2h":    {Amount: 1000, Period: 2 * time.Hour},
+		"7/1h0m1s": {Amount: 7, Period: time.Hour + time.Second},
+	}
+	for str, val := range rates {
+		parsed, err := ParseRate(str)
+		if err != nil || parsed != val || val.String() != str {
+			t.Errorf("expected %s to be %#v, got %#v (%v) and '%s'", str, val, parsed, err, val.String())
+		}
+	}
+}
+
+func TestByteSizeFieldDefaultValue(t *testing.T) {
+	t.Parallel()
+	var dest uint64
+	mf := &ManagedField{Field: NewByteSizeField(&dest, DefaultStringValue("512KiB"))}
+	if errs := mf.Consolidate(); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if mf.DefaultValue != "512KiB" {
+		t.Errorf("expected the default value to be 512KiB, but got '%s'", mf.DefaultValue)
+	}
+}
+
+func TestEnumFieldPossibleValues(t *testing.T) {
+	t.Parallel()
+	var dest string
+	cm := NewManager()
+	mf := cm.AddField(NewEnumField(&dest, []string{"a", "b"}, DefaultStringValue("a")))
+	if !reflect.DeepEqual(mf.PossibleValues, []string{"a", "b"}) {
+		t.Errorf("unexpected possible values %#v", mf.PossibleValues)
+	}
+}
+
+func TestDurationValueBinderIsOptional(t *testing.T) {
+	t.Parallel()
+
+	source := NewJSONSource([]byte(`{"timeouts": ["1s", 1500]}`))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+	var length int
+	var getElement func(int) LazySingleValueBinder
+	if err := source.From("timeouts").BindArrayValueTo(&length, &getElement).Apply(); err != nil || length != 2 {
+		t.Fatalf("unexpected length %d or error %s", length, err)
+	}
+
+	var timeouts []time.Duration
+	for i := 0; i < length; i++ {
+		durationBinder, ok := getElement(i).(DurationValueBinder)
+		if !ok {
+			t.Fatalf("expected element %d to be a DurationValueBinder", i)
+		}
+		var val time.Duration
+		if err := durationBinder.BindDurationValueTo(&val, time.Millisecond).Apply(); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		timeouts = append(timeouts, val)
+	}
+	if exp := []time.Duration{time.Second, 1500 * time.Millisecond}; !reflect.DeepEqual(timeouts, exp) {
+		t.Errorf("expected %v, got %v", exp, timeouts)
+	}
+
+	// Binders that only implement the other methods are still valid
+	var onlyLazy LazySingleValueBinder = struct{ LazySingleValueBinder }{getElement(0)}
+	if _, ok := onlyLazy.(DurationValueBinder); ok {
+		t.Errorf("expected the wrapped binder to not be a DurationValueBinder")
+	}
+}
+
+func TestParseDurationBoundaries(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input  string
+		unit   time.Duration
+		exp    time.Duration
+		expErr bool
+	}{
+		// float64(math.MaxInt64) is exactly 2^63, which doesn't fit
+		{input: "9223372036854775807", unit: time.Nanosecond, expErr: true},
+		{input: "9223372036854775808", unit: time.Nanosecond, expErr: true},
+		{input: "9223372036854774784", unit: time.Nanosecond, exp: 9223372036854774784},
+		{input: "-9223372036854775808", unit: time.Nanosecond, exp: math.MinInt64},
+		{input: "106751d23h47m16.854775807s", exp: math.MaxInt64},
+		{input: "106751d23h47m16.854775808s", expErr: true},
+		{input: "-106751d23h47m16.854775808s", exp: math.MinInt64},
+		{input: "-106751d23h47m16.854775809s", expErr: true},
+		{input: "106752d", expErr: true},
+	}
+
+	for _, tc := range testCases {
+		unit := tc.unit
+		if unit == 0 {
+			unit = time.Millisecond
+		}
+		val, err := parseDuration(tc.input, unit)
+		switch {
+		case tc.expErr && (err == nil || err.Err.Error() != "value out of range"):
+			t.Errorf("%s: expected an out of range error, got %s and %v", tc.input, val, err)
+		case !tc.expErr && (err != nil || val != tc.exp):
+			t.Errorf("%s: expected %d, got %d and %v", tc.input, tc.exp, val, err)
+		}
 	}
 }
@@ -1,6 +1,6 @@
-package croconf //nolint:dupl
+package croconf
 
-import "strconv"
+import "reflect"
 
 func uintValHelper(sources []UintValueBinder, bitSize int, saveToDest func(uint64)) func(sourceNum int) Binding {
 	return func(sourceNum int) Binding {
@@ -20,28 +20,27 @@ func uintValHelper(sources []UintValueBinder, bitSize int, saveToDest func(uint6
 	}
 }
 
-func NewUintField(dest *uint, sources ...UintValueBinder) Field {
-	return newField(dest, len(sources), uintValHelper(sources, strconv.IntSize, func(val uint64) {
-		*dest = uint(val) // this is safe, uintValHelper checks val against bitSize
+func newUintField[T Unsigned](dest *T, sources []UintValueBinder) Field {
+	bitSize := reflect.TypeOf(dest).Elem().Bits()
+	return newField(dest, len(sources), uintValHelper(sources, bitSize, func(val uint64) {
+		*dest = T(val) // this is safe, uintValHelper checks val against bitSize
 	}))
 }
 
+func NewUintField(dest *uint, sources ...UintValueBinder) Field {
+	return newUintField(dest, sources)
+}
+
 func NewUint8Field(dest *uint8, sources ...UintValueBinder) Field {
-	return newField(dest, len(sources), uintValHelper(sources, 8, func(val uint64) {
-		*dest = uint8(val) // this is safe, uintValHelper checks val against bitSize
-	}))
+	return newUintField(dest, sources)
 }
 
 func NewUint16Field(dest *uint16, sources ...UintValueBinder) Field {
-	return newField(dest, len(sources), uintValHelper(sources, 16, func(val uint64) {
-		*dest = uint16(val) // this is safe, uintValHelper checks val against bitSize
-	}))
+	return newUintField(dest, sources)
 }
 
 func NewUint32Field(dest *uint32, sources ...UintValueBinder) Field {
-	return newField(dest, len(sources), uintValHelper(sources, 32, func(val uint64) {
-		*dest = uint32(val) // this is safe, uintValHelper checks val against bitSize
-	}))
+	return newUintField(dest, sources)
 }
 
 func NewUint64Field(dest *uint64, sources ...UintValueBinder) Field {
@@ -68,14 +67,15 @@ func uintSliceHandler(newTypedSlice func(int) (add func(uint64) error, save func
 	}
 }
 
-func NewUintSliceField(dest *[]uint, sources ...ArrayValueBinder) Field {
+func newUintSliceField[T Unsigned](dest *[]T, sources []ArrayValueBinder) Field {
+	bitSize := reflect.TypeOf(dest).Elem().Elem().Bits()
 	return newArrayField(dest, sources, uintSliceHandler(func(arrLength int) (func(uint64) error, func()) {
-		newArr := make([]uint, 0, arrLength)
+		newArr := make([]T, 0, arrLength)
 		add := func(val uint64) error {
-			if err := checkUintBitsize(val, strconv.IntSize); err != nil {
+			if err := checkUintBitsize(val, bitSize); err != nil {
 				return err
 			}
-			newArr = append(newArr, uint(val)) // this is safe
+			newArr = append(newArr, T(val)) // this is safe
 			return nil
 		}
 		save := func() { *dest = newArr }
@@ -83,59 +83,22 @@ func NewUintSliceField(dest *[]uint, sources ...ArrayValueBinder) Field {
 	}))
 }
 
+func NewUintSliceField(dest *[]uint, sources ...ArrayValueBinder) Field {
+	return newUintSliceField(dest, sources)
+}
+
 func NewUint8SliceField(dest *[]uint8, sources ...ArrayValueBinder) Field {
-	return newArrayField(dest, sources, uintSliceHandler(func(arrLength int) (func(uint64) error, func()) {
-		newArr := make([]uint8, 0, arrLength)
-		add := func(val uint64) error {
-			if err := checkUintBitsize(val, 8); err != nil {
-				return err
-			}
-			newArr = append(newArr, uint8(val)) // this is safe
-			return nil
-		}
-		save := func() { *dest = newArr }
-		return add, save
-	}))
+	return newUintSliceField(dest, sources)
 }
 
 func NewUint16SliceField(dest *[]uint16, sources ...ArrayValueBinder) Field {
-	return newArrayField(dest, sources, uintSliceHandler(func(arrLength int) (func(uint64) error, func()) {
-		newArr := make([]uint16, 0, arrLength)
-		add := func(val uint64) error {
-			if err := checkUintBitsize(val, 16); err != nil {
-				return err
-			}
-			newArr = append(newArr, uint16(val)) // this is safe
-			return nil
-		}
-		save := func() { *dest = newArr }
-		return add, save
-	}))
+	return newUintSliceField(dest, sources)
 }
 
 func NewUint32SliceField(dest *[]uint32, sources ...ArrayValueBinder) Field {
-	return newArrayField(dest, sources, uintSliceHandler(func(arrLength int) (func(uint64) error, func()) {
-		newArr := make([]uint32, 0, arrLength)
-		add := func(val uint64) error {
-			if err := checkUintBitsize(val, 32); err != nil {
-				return err
-			}
-			newArr = append(newArr, uint32(val)) // this is safe
-			return nil
-		}
-		save := func() { *dest = newArr }
-		return add, save
-	}))
+	return newUintSliceField(dest, sources)
 }
 
 func NewUint64SliceField(dest *[]uint64, sources ...ArrayValueBinder) Field {
-	return newArrayField(dest, sources, uintSliceHandler(func(arrLength int) (func(uint64) error, func()) {
-		newArr := make([]uint64, 0, arrLength)
-		add := func(val uint64) error {
-			newArr = append(newArr, val)
-			return nil
-		}
-		save := func() { *dest = newArr }
-		return add, save
-	}))
+	return newUintSliceField(dest, sources)
 }
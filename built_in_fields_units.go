@@ -0,0 +1,221 @@
+package croconf
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type unitPrefix struct {
+	name       string
+	multiplier uint64
+}
+
+// unitPrefixes contains both the SI (powers of 1000) and the IEC (powers of
+// 1024) prefixes, sorted by their multipliers in descending order.
+var unitPrefixes = []unitPrefix{ //nolint:gochecknoglobals
+	{"Ei", 1 << 60}, {"E", 1e18}, {"Pi", 1 << 50}, {"P", 1e15}, {"Ti", 1 << 40}, {"T", 1e12},
+	{"Gi", 1 << 30}, {"G", 1e9}, {"Mi", 1 << 20}, {"M", 1e6}, {"Ki", 1 << 10}, {"k", 1e3},
+}
+
+// parseWithUnitPrefix parses numbers like "10", "1.5k" or "512Ki". The prefixes
+// are case-insensitive and the result must be a whole number.
+func parseWithUnitPrefix(s string) (uint64, error) {
+	numEnd := strings.IndexFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' })
+	if numEnd < 0 {
+		numEnd = len(s)
+	}
+	num, prefix := s[:numEnd], strings.TrimSpace(s[numEnd:])
+	if num == "" {
+		return 0, errors.New("expected a number")
+	}
+
+	multiplier := uint64(1)
+	if prefix != "" {
+		found := false
+		for _, up := range unitPrefixes {
+			if strings.EqualFold(prefix, up.name) {
+				multiplier, found = up.multiplier, true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown unit prefix '%s'", prefix)
+		}
+	}
+
+	if !strings.Contains(num, ".") {
+		val, err := strconv.ParseUint(num, 10, 64)
+		if err != nil || val > math.MaxUint64/multiplier {
+			return 0, errors.New("value out of range")
+		}
+		return val * multiplier, nil
+	}
+
+	val, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, errors.New("expected a number")
+	}
+	result := val * float64(multiplier)
+	if result >= math.MaxUint64 {
+		return 0, errors.New("value out of range")
+	}
+	if result != math.Trunc(result) {
+		return 0, errors.New("expected a whole number")
+	}
+	return uint64(result), nil
+}
+
+// formatWithUnitPrefix is the reverse of parseWithUnitPrefix, it uses the
+// biggest unit prefix that can exactly represent the value.
+func formatWithUnitPrefix(val uint64) string {
+	if val != 0 {
+		for _, up := range unitPrefixes {
+			if val%up.multiplier == 0 {
+				return strconv.FormatUint(val/up.multiplier, 10) + up.name
+			}
+		}
+	}
+	return strconv.FormatUint(val, 10)
+}
+
+// ByteSize is a number of bytes that can be parsed from and formatted to
+// human-readable strings like "10MB" or "512KiB". Both the SI (kB, MB, GB,
+// etc.) and the IEC (KiB, MiB, GiB, etc.) units are supported.
+type ByteSize uint64
+
+func ParseByteSize(s string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasSuffix(trimmed, "B") || strings.HasSuffix(trimmed, "b") {
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+	val, err := parseWithUnitPrefix(trimmed)
+	if err != nil {
+		return 0, err
+	}
+	return ByteSize(val), nil
+}
+
+func (bs ByteSize) String() string {
+	return formatWithUnitPrefix(uint64(bs)) + "B"
+}
+
+func (bs ByteSize) MarshalText() ([]byte, error) {
+	return []byte(bs.String()), nil
+}
+
+func (bs *ByteSize) UnmarshalText(data []byte) error {
+	val, err := ParseByteSize(string(data))
+	if err != nil {
+		return err
+	}
+	*bs = val
+	return nil
+}
+
+// Rate is an amount of something per a period of time, e.g. "100/s" or
+// "5k/1m". Like ByteSize, the amount supports SI and IEC unit prefixes.
+type Rate struct {
+	Amount uint64
+	Period time.Duration
+}
+
+// ParseRate parses rates like "100/s", "5/1m" or "1.5k/2h". If no period is
+// specified, e.g. "100", it's one second.
+func ParseRate(s string) (Rate, error) {
+	amount, period := strings.TrimSpace(s), "s"
+	if slashPos := strings.IndexRune(amount, '/'); slashPos >= 0 {
+		amount, period = strings.TrimSpace(amount[:slashPos]), strings.TrimSpace(amount[slashPos+1:])
+	}
+
+	amountVal, err := parseWithUnitPrefix(amount)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period // e.g. "s" -> "1s"
+	}
+	periodVal, bindErr := parseDuration(period, time.Second)
+	if bindErr != nil || periodVal <= 0 {
+		return Rate{}, fmt.Errorf("invalid period '%s', expected something like s, m or 10s", period)
+	}
+	return Rate{Amount: amountVal, Period: periodVal}, nil
+}
+
+func (r Rate) String() string {
+	period := r.Period.String()
+	switch r.Period {
+	case time.Millisecond:
+		period = "ms"
+	case time.Second:
+		period = "s"
+	case time.Minute:
+		period = "m"
+	case time.Hour:
+		period = "h"
+	default:
+		// Remove the noise, e.g. "1h30m0s" -> "1h30m", "2h0m0s" -> "2h"
+		if strings.HasSuffix(period, "m0s") {
+			period = period[:len(period)-2]
+		}
+		if strings.HasSuffix(period, "h0m") {
+			period = period[:len(period)-2]
+		}
+	}
+	return formatWithUnitPrefix(r.Amount) + "/" + period
+}
+
+// PerSecond returns how many of something there are in a second.
+func (r Rate) PerSecond() float64 {
+	return float64(r.Amount) / r.Period.Seconds()
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalText(data []byte) error {
+	val, err := ParseRate(string(data))
+	if err != nil {
+		return err
+	}
+	*r = val
+	return nil
+}
+
+// NewByteSizeField creates a field for byte sizes like "10MB" or "512KiB",
+// which are checked against the size of the destination type, e.g. a uint16
+// destination can't be more than 64KiB.
+func NewByteSizeField[T Unsigned](dest *T, sources ...StringValueBinder) Field {
+	bitSize := reflect.TypeOf(dest).Elem().Bits()
+	f := newField(dest, len(sources), parsedStringHelper(sources, "byte size", func(s string) error {
+		val, err := ParseByteSize(s)
+		if err != nil {
+			return err
+		}
+		if err := checkUintBitsize(uint64(val), bitSize); err != nil {
+			return err
+		}
+		*dest = T(val) // this is safe, we just checked the bitSize
+		return nil
+	}))
+	return &formattedField{field: f, format: func() string {
+		return ByteSize(*dest).String()
+	}}
+}
+
+func NewRateField(dest *Rate, sources ...StringValueBinder) Field {
+	return newField(dest, len(sources), parsedStringHelper(sources, "rate", func(s string) error {
+		val, err := ParseRate(s)
+		if err != nil {
+			return err
+		}
+		*dest = val
+		return nil
+	}))
+}
@@ -34,9 +34,32 @@ func NewCallbackBindingFromSource(source Source, boundName string, callback func
 	}
 }
 
+// wrappedBinding is a binding with a new callback that otherwise behaves like
+// the original binding. Its Source() is resolved lazily, since some bindings,
+// e.g. the ones of SourceLayeredJSON, only know it after they were applied.
+type wrappedBinding struct {
+	*callbackBinding
+	orig BindingFromSource
+}
+
+func (wb *wrappedBinding) Source() Source {
+	return wb.orig.Source()
+}
+
+func (wb *wrappedBinding) BoundName() string {
+	return wb.orig.BoundName()
+}
+
+func (wb *wrappedBinding) Usage() string {
+	if withUsage, ok := wb.orig.(BindingWithUsage); ok {
+		return withUsage.Usage()
+	}
+	return wb.orig.BoundName()
+}
+
 func wrapBinding(origBinding Binding, newCallback func() error) Binding {
 	if fromSource, ok := origBinding.(BindingFromSource); ok {
-		return NewCallbackBindingFromSource(fromSource.Source(), fromSource.BoundName(), newCallback)
+		return &wrappedBinding{callbackBinding: &callbackBinding{apply: newCallback}, orig: fromSource}
 	} else {
 		return NewCallbackBinding(newCallback)
 	}
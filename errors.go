@@ -1,9 +1,11 @@
 package croconf
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 var ErrorMissing = errors.New("field is missing in config source") // TODO: remove?
@@ -48,18 +50,129 @@ func (e *BindValueError) withFuncName(funcName string) *BindValueError {
 	return NewBindValueError(funcName, e.Input, e.Err)
 }
 
+// JSONSourceInitError is returned when a JSON document can't be parsed. Its
+// message contains the location of the problem, followed by the offending line
+// of the document with a caret under the exact column.
 type JSONSourceInitError struct {
-	Data []byte // the failing data input
-	Err  error
+	Data     []byte // the failing data input
+	Location string // e.g. the file name or the source name
+	Line     int    // 1-based, 0 if unknown
+	Column   int
+	Err      error
 }
 
+// NewJSONSourceInitError creates an error for the given JSON parsing error. If
+// it's a *json.SyntaxError or a *json.UnmarshalTypeError, its offset is used
+// to find the line and column of the problem in data.
 func NewJSONSourceInitError(data []byte, err error) *JSONSourceInitError {
-	return &JSONSourceInitError{Data: data, Err: err}
+	initErr := &JSONSourceInitError{Data: data, Location: "json", Err: err}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr) && syntaxErr.Offset > 0:
+		initErr.Line, initErr.Column = textPosition(data, int(syntaxErr.Offset)-1)
+	case errors.As(err, &typeErr) && typeErr.Offset > 0:
+		initErr.Line, initErr.Column = textPosition(data, int(typeErr.Offset)-1)
+	}
+	return initErr
 }
 
 // Error implements error interface
 func (e *JSONSourceInitError) Error() string {
-	return "source json initialization failed: data=" + string(e.Data)
+	msg := formatLocation(e.Location, e.Line, e.Column) + ": " + e.Err.Error()
+	if e.Line <= 0 {
+		return msg
+	}
+
+	lines := strings.Split(string(e.Data), "\n")
+	if e.Line > len(lines) {
+		return msg
+	}
+	line := strings.TrimRight(lines[e.Line-1], "\r")
+
+	// Keep any tabs before the column, so the caret is aligned with it
+	var caret strings.Builder
+	for i := 0; i < e.Column-1 && i < len(line); i++ {
+		if line[i] == '\t' {
+			caret.WriteByte('\t')
+		} else {
+			caret.WriteByte(' ')
+		}
+	}
+	return msg + "\n    " + line + "\n    " + caret.String() + "^"
 }
 
 func (e *JSONSourceInitError) Unwrap() error { return e.Err }
+
+// SourceParseError is returned when a source can't parse its contents. Line
+// and Column point to the exact location of the problem, they are 1-based.
+type SourceParseError struct {
+	Location string // e.g. the file name or the source name
+	Line     int
+	Column   int
+	Err      error
+}
+
+func NewSourceParseError(location string, line, column int, err error) *SourceParseError {
+	return &SourceParseError{Location: location, Line: line, Column: column, Err: err}
+}
+
+// Error implements error interface
+func (e *SourceParseError) Error() string {
+	return formatLocation(e.Location, e.Line, e.Column) + ": " + e.Err.Error()
+}
+
+func (e *SourceParseError) Unwrap() error { return e.Err }
+
+// SourceValueError is returned when a value is present in a source, but it
+// can't be bound to the destination, e.g. because it has the wrong type.
+type SourceValueError struct {
+	Location string // e.g. config.yaml:14:9
+	Name     string // the bound name, e.g. dns.ttl
+	Err      error
+}
+
+func NewSourceValueError(location, name string, err error) *SourceValueError {
+	return &SourceValueError{Location: location, Name: name, Err: err}
+}
+
+// Error implements error interface
+func (e *SourceValueError) Error() string {
+	return e.Location + ": " + e.Name + ": " + e.Err.Error()
+}
+
+func (e *SourceValueError) Unwrap() error { return e.Err }
+
+// UnknownValueError is reported for values in a source that weren't used by
+// any field, e.g. a mistyped JSON key. Suggestion is the closest known name,
+// if there is one that is similar enough.
+type UnknownValueError struct {
+	Location   string // e.g. config.json:3:5, can be empty
+	Kind       string // e.g. key or flag
+	Name       string
+	Suggestion string
+}
+
+func NewUnknownValueError(location, kind, name, suggestion string) *UnknownValueError {
+	return &UnknownValueError{Location: location, Kind: kind, Name: name, Suggestion: suggestion}
+}
+
+// Error implements error interface
+func (e *UnknownValueError) Error() string {
+	msg := "unknown " + e.Kind + " " + e.Name
+	if e.Location != "" {
+		msg = e.Location + ": " + msg
+	}
+	if e.Suggestion != "" {
+		msg += ", did you mean " + e.Suggestion + "?"
+	}
+	return msg
+}
+
+// formatLocation returns locations in the usual file:line:column format.
+func formatLocation(name string, line, column int) string {
+	if line <= 0 {
+		return name
+	}
+	return fmt.Sprintf("%s:%d:%d", name, line, column)
+}
@@ -3,7 +3,9 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log"
 	"strings"
 
 	"go.k6.io/croconf"
@@ -48,8 +50,8 @@ func GetSubcommandHandler(
 	)
 
 	cm.AddField(
-		croconf.NewStringField(&subCommand, scBinders...),
-		croconf.WithDescription(fmt.Sprintf("sub-command (%s)", possibleValues)),
+		croconf.NewEnumField(&subCommand, subCommandIDs, scBinders...),
+		croconf.WithDescription("sub-command"),
 	)
 
 	return func() error {
@@ -62,14 +64,15 @@ func GetSubcommandHandler(
 			}
 		}
 
-		subCmd, ok := subCommandsByID[subCommand]
-		if !ok {
-			return fmt.Errorf("invalid sub-command '%s', %s", subCommand, possibleValues)
-		}
+		// This is always valid, otherwise the consolidation would have failed
+		subCmd := subCommandsByID[subCommand]
 
 		if err := subCmd.AddConfigOptions(); err != nil {
 			return err
 		}
+		if err := checkWarnings(cm); err != nil {
+			return err
+		}
 
 		if showHelp {
 			fmt.Printf("Help for subcommand %s:\n\n", subCommand)
@@ -87,15 +90,32 @@ func getHelp(cm *croconf.Manager) string {
 	for _, field := range cm.Fields() {
 		fmt.Fprintf(&sb, "Field '%s' (%s):\n", field.Name, field.Description)
 		fmt.Fprintf(&sb, "\tDefault value: %s\n", field.DefaultValue)
+		if len(field.PossibleValues) > 0 {
+			fmt.Fprintf(&sb, "\tPossible values: %s\n", strings.Join(field.PossibleValues, ", "))
+		}
 		for _, b := range field.Bindings() {
 			if fromSource, ok := b.(croconf.BindingFromSource); ok && fromSource.Source() != nil {
-				fmt.Fprintf(
-					&sb, "\tFrom %s: %s\n",
-					fromSource.Source().GetName(), fromSource.BoundName(),
-				)
+				usage := fromSource.BoundName()
+				if withUsage, ok := b.(croconf.BindingWithUsage); ok {
+					usage = withUsage.Usage()
+				}
+				fmt.Fprintf(&sb, "\tFrom %s: %s\n", fromSource.Source().GetName(), usage)
 			}
 		}
 	}
 
 	return sb.String()
 }
+
+// checkWarnings logs the consolidation warnings, e.g. unknown JSON keys, but
+// returns an error for unknown CLI flags, since all flags are known by now.
+func checkWarnings(cm *croconf.Manager) error {
+	for _, warning := range cm.Warnings() {
+		var unknownErr *croconf.UnknownValueError
+		if errors.As(warning, &unknownErr) && unknownErr.Kind == "flag" {
+			return warning
+		}
+		log.Printf("warning: %s", warning)
+	}
+	return nil
+}
@@ -39,8 +39,6 @@ func runCommand(
 			jsonSource = croconf.NewJSONSource(jsonConfigContents)
 			scriptConf = config.NewScriptConfig(configManager, globalConf, cliSource, envVarsSource, jsonSource)
 
-			// TODO: error out if we see unknown CLI flags or JSON options
-
 			return configManager.Consolidate()
 		},
 		Run: func() error {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"os"
+
 	"go.k6.io/croconf"
 	"go.k6.io/croconf/examples/croconf-complex-example/config"
 )
@@ -10,6 +12,9 @@ func getSingleValCommand(
 	cliSource *croconf.SourceCLI, envVarsSource *croconf.SourceEnvVars,
 ) SubCommand {
 	var singleTestValue string
+	// These variables predate the K6_ prefix of envVarsSource, so they are
+	// read without it, to keep their old names working.
+	unprefixedEnvSource := croconf.NewSourceFromEnv(os.Environ())
 
 	return SubCommand{
 		Command: "single",
@@ -18,8 +23,8 @@ func getSingleValCommand(
 				croconf.NewStringField(
 					&singleTestValue,
 					croconf.DefaultStringValue("foobar"),
-					envVarsSource.From("SIMPLE_TEST_VAL_DEPRECATED"), // TODO: warn for deprecated
-					envVarsSource.From("SIMPLE_TEST_VAL"),
+					unprefixedEnvSource.From("SIMPLE_TEST_VAL_DEPRECATED"), // TODO: warn for deprecated
+					unprefixedEnvSource.From("SIMPLE_TEST_VAL"),
 					cliSource.FromNameAndShorthand("test", "t"),
 				),
 				croconf.WithDescription("just a simple test value outside of a struct, but still not global"),
@@ -19,7 +19,7 @@ func NewGlobalConfig(
 		croconf.NewStringField(
 			&conf.JSONConfigPath,
 			croconf.DefaultStringValue("./config.json"),
-			envVarsSource.From("K6_CONFIG"),
+			envVarsSource.From("CONFIG"),
 			cliSource.FromNameAndShorthand("config", "c"),
 		),
 		croconf.WithDescription("path to k6 JSON config file"),
@@ -28,7 +28,7 @@ func NewGlobalConfig(
 	cm.AddField(
 		croconf.NewBoolField(
 			&conf.Verbose,
-			envVarsSource.From("K6_VERBOSE"),
+			envVarsSource.From("VERBOSE"),
 			cliSource.FromNameAndShorthand("verbose", "v"),
 		),
 		croconf.WithDescription("enable verbose logging"),
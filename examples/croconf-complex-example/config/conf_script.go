@@ -42,13 +42,23 @@ func NewScriptConfig( //nolint: funlen
 		// Marshaler:    jsonSource.NewMarshaller(),
 	}
 
+	// Derives the JSON, environment variable and CLI flag names from a single
+	// canonical name, e.g. userAgent, K6_USER_AGENT and --user-agent
+	names := croconf.NewNamingConvention(jsonSource, envVarsSource, cliSource)
+	userAgent := names.Name("userAgent")
+	vus := names.Name("vus", croconf.WithCLIShorthand("u"))
+	throw := names.Name("throw", croconf.WithCLIShorthand("w"))
+	duration := names.Name("duration", croconf.WithCLIShorthand("d"))
+	tiny := names.Name("tiny")
+	tinyArr := names.Name("tinyArr", croconf.WithCLIShorthand("a"))
+
 	cm.AddField(
 		croconf.NewStringField(
 			&conf.UserAgent,
 			croconf.DefaultStringValue("croconf example demo v0.0.1 (https://k6.io/)"),
-			jsonSource.From("userAgent"),
-			envVarsSource.From("K6_USER_AGENT"),
-			cliSource.FromName("user-agent"),
+			userAgent.JSON(),
+			userAgent.Env(),
+			userAgent.CLI(),
 		),
 		croconf.WithDescription("user agent for http requests"),
 	)
@@ -57,9 +67,9 @@ func NewScriptConfig( //nolint: funlen
 		croconf.NewInt64Field(
 			&conf.VUs,
 			croconf.DefaultIntValue(1),
-			jsonSource.From("vus"),
-			envVarsSource.From("K6_VUS"),
-			cliSource.FromNameAndShorthand("vus", "u"),
+			vus.JSON(),
+			vus.Env(),
+			vus.CLI(),
 		),
 		croconf.WithDescription("number of virtual users"),
 		croconf.IsRequired(),
@@ -68,10 +78,10 @@ func NewScriptConfig( //nolint: funlen
 	cm.AddField(
 		croconf.NewBoolField(
 			&conf.Throw,
-			// TODO: croconf.DefaultBoolValue(false),
-			jsonSource.From("throw"),
-			envVarsSource.From("K6_THROW"),
-			cliSource.FromNameAndShorthand("throw", "w"),
+			croconf.DefaultBoolValue(false),
+			throw.JSON(),
+			throw.Env(),
+			throw.CLI(),
 		),
 		croconf.WithDescription("throw warnings (like failed http requests) as errors"),
 	)
@@ -80,9 +90,9 @@ func NewScriptConfig( //nolint: funlen
 		croconf.NewTextBasedField(
 			&conf.Duration,
 			croconf.DefaultStringValue("1s"),
-			jsonSource.From("duration"),
-			envVarsSource.From("K6_DURATION"),
-			cliSource.FromNameAndShorthand("duration", "d"),
+			duration.JSON(),
+			duration.Env(),
+			duration.CLI(),
 		),
 		croconf.WithDescription("test duration"),
 	)
@@ -106,16 +116,16 @@ func NewScriptConfig( //nolint: funlen
 	cm.AddField(croconf.NewInt8Field(
 		&conf.Tiny,
 		croconf.DefaultIntValue(1),
-		jsonSource.From("tiny"),
-		envVarsSource.From("K6_TINY"),
-		cliSource.FromName("tiny"),
+		tiny.JSON(),
+		tiny.Env(),
+		tiny.CLI(),
 	))
 
 	cm.AddField(croconf.NewInt8SliceField(
 		&conf.TinyArr,
-		jsonSource.From("tinyArr"),
-		envVarsSource.From("K6_TINY_ARR"),
-		cliSource.FromNameAndShorthand("tiny-arr", "a"),
+		tinyArr.JSON(),
+		tinyArr.Env(),
+		tinyArr.CLI(),
 		// TODO: other sources and defaults
 	))
 
@@ -9,8 +9,11 @@ import (
 )
 
 func main() {
-	cliSource := croconf.NewSourceFromCLIFlags(os.Args[1:])
-	envVarsSource := croconf.NewSourceFromEnv(os.Environ())
+	cliSource := croconf.NewSourceFromCLIFlags(
+		// The sub-command flags are only known after the second consolidation
+		os.Args[1:], croconf.WithUnknownFlagsAsWarnings(),
+	)
+	envVarsSource := croconf.NewSourceFromEnv(os.Environ(), croconf.WithPrefix("K6_"))
 	configManager := croconf.NewManager(
 		croconf.WithDefaultSourceOfFieldNames(envVarsSource),
 	)
@@ -20,7 +23,7 @@ func main() {
 	subCommands := getSubcommands(configManager, globalConf, cliSource, envVarsSource)
 
 	handler, err := GetSubcommandHandler(configManager, cliSource, subCommands, []croconf.StringValueBinder{
-		envVarsSource.From("K6_SUB_COMMAND"),
+		envVarsSource.From("SUB_COMMAND"),
 		cliSource.FromPositionalArg(1),
 	})
 	if err != nil {
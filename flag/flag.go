@@ -1,25 +1,45 @@
 package flag
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 )
 
 type Parser struct {
 	unaries map[string]struct{}
 	slices  map[string]struct{}
+	values  map[string]struct{}
+
+	// longNames maps both names of the unary flags to the long one and
+	// negations maps the no-<name> flags to the long names of their flags
+	longNames map[string]string
+	negations map[string]string
+
+	stopAtDoubleDash bool
 }
 
 func NewParser() *Parser {
 	return &Parser{
-		unaries: make(map[string]struct{}),
-		slices:  make(map[string]struct{}),
+		unaries:   make(map[string]struct{}),
+		slices:    make(map[string]struct{}),
+		values:    make(map[string]struct{}),
+		longNames: make(map[string]string),
+		negations: make(map[string]string),
 	}
 }
 
+// RegisterUnary registers a flag that doesn't need a value, e.g. --bool. It
+// can be turned off with either --bool=false or its --no-bool negation.
 func (p *Parser) RegisterUnary(long, short string) {
 	p.unaries[long] = struct{}{}
+	p.longNames[long] = long
 	if short != "" {
 		p.unaries[short] = struct{}{}
+		p.longNames[short] = long
+	}
+	if len(long) > 1 {
+		p.negations["no-"+long] = long
 	}
 }
 
@@ -30,6 +50,35 @@ func (p *Parser) RegisterSlice(long, short string) {
 	}
 }
 
+// RegisterValue registers a flag that needs a value, e.g. --name=value. Such
+// flags are parsed even if they aren't registered, but registering them gives
+// them priority over the no-<name> negations of unary flags, e.g. a --no-proxy
+// value flag is used instead of the negation of a --proxy unary flag.
+func (p *Parser) RegisterValue(long, short string) {
+	p.values[long] = struct{}{}
+	if short != "" {
+		p.values[short] = struct{}{}
+	}
+}
+
+// negationOf returns the long name of the unary flag that is negated by the
+// given no-<name> flag, unless a flag with that exact name was registered.
+func (p *Parser) negationOf(name string) (string, bool) {
+	_, isValue := p.values[name]
+	_, isSlice := p.slices[name]
+	if isValue || isSlice {
+		return "", false
+	}
+	long, ok := p.negations[name]
+	return long, ok
+}
+
+// StopAtDoubleDash makes the parser stop at the first -- argument. All of the
+// arguments after it are returned by Set.Passthrough(), without parsing them.
+func (p *Parser) StopAtDoubleDash() {
+	p.stopAtDoubleDash = true
+}
+
 // TODO: split apart and remove nolint
 //nolint: funlen,gocognit
 func (p *Parser) Parse(tt []string) (*Set, error) {
@@ -43,6 +92,37 @@ func (p *Parser) Parse(tt []string) (*Set, error) {
 		posArgs: make([]string, 0, len(args)),
 	}
 
+	seen := make(map[string]struct{})
+	see := func(name string) {
+		if _, ok := seen[name]; !ok {
+			seen[name] = struct{}{}
+			fs.names = append(fs.names, name)
+		}
+	}
+
+	// setUnary sets the value of a unary flag and removes any value that was
+	// set with its other name, so that the last given one always wins
+	set, negated := make(map[string]bool), make(map[string]bool)
+	setUnary := func(key, value string, negation bool) {
+		long := p.longNames[key]
+		for name, l := range p.longNames {
+			if l == long && name != key {
+				delete(fs.flags, name)
+			}
+		}
+		fs.flags[key] = value
+
+		wasConflict := set[long] && negated[long]
+		if b, err := strconv.ParseBool(value); negation {
+			negated[long] = true
+		} else if err == nil && b {
+			set[long] = true
+		}
+		if !wasConflict && set[long] && negated[long] {
+			fs.conflicts = append(fs.conflicts, long)
+		}
+	}
+
 	// remove the single or double dash
 	nohypens := func(s string) string {
 		if s[0] != '-' {
@@ -56,7 +136,26 @@ func (p *Parser) Parse(tt []string) (*Set, error) {
 		return s[2:]
 	}
 
-	addflag := func(key, v string) {
+	var err error
+	addflag := func(dashes, key, v string) {
+		see(dashes + key)
+		if _, ok := p.unaries[key]; ok {
+			setUnary(key, v, false)
+			return
+		}
+		// --no-bool=true is the same as --no-bool and --no-bool=false is the
+		// same as --bool
+		if long, ok := p.negationOf(key); ok {
+			b, parseErr := strconv.ParseBool(v)
+			if parseErr != nil {
+				if err == nil {
+					err = fmt.Errorf("invalid value '%s' for %s%s, expected a boolean", v, dashes, key)
+				}
+				return
+			}
+			setUnary(long, strconv.FormatBool(!b), b)
+			return
+		}
 		if _, ok := p.slices[key]; !ok {
 			fs.flags[key] = v
 		} else {
@@ -64,7 +163,6 @@ func (p *Parser) Parse(tt []string) (*Set, error) {
 		}
 	}
 
-	var err error
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 
@@ -77,28 +175,42 @@ func (p *Parser) Parse(tt []string) (*Set, error) {
 		case strings.HasPrefix(arg, "--"):
 			// -- example.go
 			if arg == "--" {
+				if p.stopAtDoubleDash {
+					fs.passthrough = append(fs.passthrough, args[i+1:]...)
+					return &fs, nil
+				}
 				continue
 			}
 
 			arg = nohypens(arg)
 
 			// --opt=value
-			opt := strings.Split(arg, "=")
+			opt := strings.SplitN(arg, "=", 2)
 			if len(opt) == 2 {
-				addflag(opt[0], opt[1])
+				addflag("--", opt[0], opt[1])
 				continue
 			}
 
 			// --bool cmd1
 			if _, ok := p.unaries[arg]; ok {
-				fs.flags[arg] = "true"
+				see("--" + arg)
+				setUnary(arg, "true", false)
+				continue
+			}
+
+			// --no-bool cmd1
+			if long, ok := p.negationOf(arg); ok {
+				see("--" + arg)
+				setUnary(long, "false", true)
 				continue
 			}
 
 			// --opt value
 			if next != nil {
-				addflag(arg, *next)
+				addflag("--", arg, *next)
 				args = args[:i+copy(args[i:], args[i+1:])]
+			} else {
+				see("--" + arg) // without a value, but it can still be unknown
 			}
 
 		case strings.HasPrefix(arg, "-"):
@@ -106,31 +218,40 @@ func (p *Parser) Parse(tt []string) (*Set, error) {
 
 			if len(arg) > 1 {
 				// -o=value
-				opt := strings.Split(arg, "=")
+				opt := strings.SplitN(arg, "=", 2)
 				if len(opt) == 2 {
-					addflag(opt[0], opt[1])
+					addflag("-", opt[0], opt[1])
 					continue
 				}
 
 				// -ob
+				allUnaries := true
 				for i := 0; i < len(arg); i++ {
 					if _, ok := p.unaries[string(arg[i])]; ok {
-						fs.flags[string(arg[i])] = "true"
+						see("-" + string(arg[i]))
+						setUnary(string(arg[i]), "true", false)
 						continue
 					}
+					allUnaries = false
+				}
+				if allUnaries {
+					continue
 				}
 			}
 
 			// -b cmd1
 			if _, ok := p.unaries[arg]; ok {
-				fs.flags[arg] = "true"
+				see("-" + arg)
+				setUnary(arg, "true", false)
 				continue
 			}
 
 			// -o value
 			if next != nil {
-				addflag(arg, *next)
+				addflag("-", arg, *next)
 				args = args[:i+copy(args[i:], args[i+1:])]
+			} else {
+				see("-" + arg) // without a value, but it can still be unknown
 			}
 		default:
 			fs.posArgs = append(fs.posArgs, arg)
@@ -143,6 +264,28 @@ type Set struct {
 	slices  map[string][]string
 	flags   map[string]string
 	posArgs []string
+
+	names       []string // all given flags, with their dashes, e.g. --vus or -u
+	passthrough []string
+	conflicts   []string
+}
+
+// Conflicts returns the long names of the unary flags that were both set, e.g.
+// with --bool or --bool=true, and negated with --no-bool.
+func (fs Set) Conflicts() []string {
+	return fs.conflicts
+}
+
+// Names returns the names of all given flags with their dashes, e.g. --vus or
+// -u, in the order they were first seen.
+func (fs Set) Names() []string {
+	return fs.names
+}
+
+// Passthrough returns the unparsed arguments after the first --, if the parser
+// was configured to stop there with StopAtDoubleDash().
+func (fs Set) Passthrough() []string {
+	return fs.passthrough
 }
 
 func (fs Set) Positional(i uint) (string, bool) {
@@ -127,6 +127,21 @@ func TestParse(t *testing.T) {
 		}
 	})
 
+	t.Run("LongOptionWithEqualsInValue", func(t *testing.T) {
+		t.Parallel()
+		args := []string{"--tag=key=value", "--tag", "foo=bar"}
+
+		p := NewParser()
+		p.RegisterSlice("tag", "")
+		fs, err := p.Parse(args)
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual(fs.slices["tag"], []string{"key=value", "foo=bar"}) {
+			t.Errorf("unexpected slice %v", fs.slices["tag"])
+		}
+	})
+
 	t.Run("ShortOption", func(t *testing.T) {
 		t.Parallel()
 		tests := []struct {
@@ -176,3 +191,126 @@ func TestParse(t *testing.T) {
 		}
 	})
 }
+
+func TestParseNames(t *testing.T) {
+	t.Parallel()
+	args := []string{"-rb", "cmd1", "--user=u1", "-o", "file.json", "--user", "u2", "--roo"}
+
+	p := NewParser()
+	p.RegisterUnary("roo", "r")
+	p.RegisterUnary("boo", "b")
+	fs, err := p.Parse(args)
+	if err != nil {
+		t.Error(err)
+	}
+	if exp := []string{"-r", "-b", "--user", "-o", "--roo"}; !reflect.DeepEqual(fs.Names(), exp) {
+		t.Errorf("expected names %v, got %v", exp, fs.Names())
+	}
+	if !reflect.DeepEqual(fs.posArgs, []string{"cmd1"}) {
+		t.Errorf("unexpected positional arguments %v", fs.posArgs)
+	}
+}
+
+func TestParseStopAtDoubleDash(t *testing.T) {
+	t.Parallel()
+	args := []string{"run", "--user=u1", "--", "--user=u2", "hello.go"}
+
+	p := NewParser()
+	p.StopAtDoubleDash()
+	fs, err := p.Parse(args)
+	if err != nil {
+		t.Error(err)
+	}
+	if u, _ := fs.Option("user", ""); u != "u1" {
+		t.Errorf("unexpected user value %s", u)
+	}
+	if !reflect.DeepEqual(fs.posArgs, []string{"run"}) {
+		t.Errorf("unexpected positional arguments %v", fs.posArgs)
+	}
+	if exp := []string{"--user=u2", "hello.go"}; !reflect.DeepEqual(fs.Passthrough(), exp) {
+		t.Errorf("expected passthrough arguments %v, got %v", exp, fs.Passthrough())
+	}
+}
+
+func TestParseNegation(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in        []string
+		exp       map[string]string
+		conflicts []string
+	}{
+		{in: []string{"--no-roo", "cmd1"}, exp: map[string]string{"roo": "false"}},
+		{in: []string{"--roo", "--no-roo"}, exp: map[string]string{"roo": "false"}, conflicts: []string{"roo"}},
+		{in: []string{"--no-roo", "-r"}, exp: map[string]string{"r": "true"}, conflicts: []string{"roo"}},
+		{in: []string{"-r", "--no-roo", "--roo=1"}, exp: map[string]string{"roo": "1"}, conflicts: []string{"roo"}},
+		{in: []string{"--roo=false", "--no-roo"}, exp: map[string]string{"roo": "false"}},
+		{in: []string{"--no-boo", "--roo"}, exp: map[string]string{"boo": "false", "roo": "true"}},
+	}
+
+	for _, tt := range tests {
+		p := NewParser()
+		p.RegisterUnary("roo", "r")
+		p.RegisterUnary("boo", "")
+		fs, err := p.Parse(tt.in)
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual(fs.flags, tt.exp) {
+			t.Errorf("unexpected flags %v %+v", tt.in, fs.flags)
+		}
+		if !reflect.DeepEqual(fs.Conflicts(), tt.conflicts) {
+			t.Errorf("unexpected conflicts %v %+v", tt.in, fs.Conflicts())
+		}
+	}
+}
+
+func TestParseNegationWithValue(t *testing.T) {
+	t.Parallel()
+	p := NewParser()
+	p.RegisterUnary("roo", "r")
+
+	fs, err := p.Parse([]string{"--no-roo=false"})
+	if err != nil || !reflect.DeepEqual(fs.flags, map[string]string{"roo": "true"}) {
+		t.Errorf("unexpected flags %+v, error %v", fs.flags, err)
+	}
+	fs, err = p.Parse([]string{"-r", "--no-roo=true"})
+	if err != nil || !reflect.DeepEqual(fs.flags, map[string]string{"roo": "false"}) {
+		t.Errorf("unexpected flags %+v, error %v", fs.flags, err)
+	}
+	if !reflect.DeepEqual(fs.Conflicts(), []string{"roo"}) {
+		t.Errorf("unexpected conflicts %+v", fs.Conflicts())
+	}
+	if _, err = p.Parse([]string{"--no-roo=maybe"}); err == nil || err.Error() != "invalid value 'maybe' for --no-roo, expected a boolean" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestParseNegationCollision(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in  []string
+		exp map[string]string
+	}{
+		{in: []string{"--no-proxy=localhost", "--proxy"}, exp: map[string]string{"no-proxy": "localhost", "proxy": "true"}},
+		{in: []string{"--no-proxy", "localhost", "cmd1"}, exp: map[string]string{"no-proxy": "localhost"}},
+		{in: []string{"--no-roo=a", "-r"}, exp: map[string]string{"r": "true"}},
+	}
+
+	for _, tt := range tests {
+		p := NewParser()
+		p.RegisterUnary("proxy", "")
+		p.RegisterValue("no-proxy", "")
+		p.RegisterUnary("roo", "r")
+		p.RegisterSlice("no-roo", "")
+		fs, err := p.Parse(tt.in)
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual(fs.flags, tt.exp) {
+			t.Errorf("unexpected flags %v %+v", tt.in, fs.flags)
+		}
+		if len(fs.Conflicts()) != 0 {
+			t.Errorf("unexpected conflicts %v %+v", tt.in, fs.Conflicts())
+		}
+	}
+}
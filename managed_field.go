@@ -12,16 +12,21 @@ type ManagedField struct {
 	wasConsolidated       bool
 	lastBindingFromSource BindingFromSource // nil for default value
 
-	Name         string
-	DefaultValue string
-	Description  string
-	Required     bool
-	Validator    func() error
+	Name           string
+	DefaultValue   string
+	Description    string
+	Required       bool
+	Validator      func() error
+	PossibleValues []string // e.g. for enums, can be used in help texts
 	// TODO: other meta information? e.g. deprecation warnings, usage
-	// information and examples, possible values, annotations, etc.
+	// information and examples, annotations, etc.
 }
 
 func (mf *ManagedField) getCurrentValueAsString() string {
+	if ff, ok := mf.Field.(interface{ currentValueString() string }); ok {
+		return ff.currentValueString()
+	}
+
 	dest := mf.Destination()
 	if stringer, ok := dest.(fmt.Stringer); ok {
 		return stringer.String()
@@ -29,15 +34,22 @@ func (mf *ManagedField) getCurrentValueAsString() string {
 
 	// TODO: check for encoding.TextMarshaler?
 
-	// Since the destination is likely a pointer, we dereference it here
-	value := reflect.Indirect(reflect.ValueOf(dest)).Interface()
-	if stringer, ok := value.(fmt.Stringer); ok {
-		return stringer.String()
+	// Since the destination is likely a pointer, we dereference it here. For
+	// optional fields, it will be a pointer to a pointer that may be nil.
+	value := reflect.ValueOf(dest)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return ""
+		}
+		if stringer, ok := value.Interface().(fmt.Stringer); ok {
+			return stringer.String()
+		}
 	}
 
 	// TODO: check for encoding.TextMarshaler?
 
-	return fmt.Sprintf("%v", value)
+	return fmt.Sprintf("%v", value.Interface())
 }
 
 func (mf *ManagedField) Consolidate() []error {
@@ -115,3 +127,11 @@ func IsRequired() ManagedFieldOption {
 		mfield.Required = true
 	}
 }
+
+// WithPossibleValues sets the values a field can have. It's set automatically
+// for fields like NewEnumField, but it can be useful for custom fields.
+func WithPossibleValues(values ...string) ManagedFieldOption {
+	return func(mfield *ManagedField) {
+		mfield.PossibleValues = values
+	}
+}
@@ -13,6 +13,8 @@ type Manager struct {
 	fieldsByDest map[interface{}]*ManagedField
 
 	defaultSourceOfFieldNames Source
+	strictUnknownValues       bool
+	warnings                  []error
 }
 
 type ManagerOption func(*Manager)
@@ -68,6 +70,9 @@ func (m *Manager) AddField(field Field, options ...ManagedFieldOption) *ManagedF
 	mf := &ManagedField{
 		Field: field,
 	}
+	if withValues, ok := field.(interface{ PossibleValues() []string }); ok {
+		mf.PossibleValues = withValues.PossibleValues()
+	}
 
 	for _, opt := range options {
 		opt(mf)
@@ -105,8 +110,16 @@ func (m *Manager) Fields() []*ManagedField {
 	return m.fields
 }
 
+// Warnings returns the problems that were found during the last Consolidate()
+// call, but which weren't treated as errors, e.g. unknown values in sources
+// when WithStrictUnknownValues() isn't used.
+func (m *Manager) Warnings() []error {
+	return m.warnings
+}
+
 func (m *Manager) Consolidate() error {
 	var errs []error
+	m.warnings = nil
 
 	for _, s := range m.sources {
 		err := s.Initialize()
@@ -127,14 +140,49 @@ func (m *Manager) Consolidate() error {
 		return consolidateErrorMessage(errs, "Config value errors: ")
 	}
 
+	unknownErrs, unknownWarnings := m.unknownValues()
+	if len(unknownErrs) > 0 {
+		return consolidateErrorMessage(unknownErrs, "Unknown config values: ")
+	}
+	m.warnings = append(m.warnings, unknownWarnings...)
+
+	return consolidateErrorMessage(m.validateFields(), "Validation errors: ")
+}
+
+// strictUnknownValuesSource is implemented by sources that can require their
+// unknown values to always be errors, e.g. SourceCLI for the unknown flags.
+type strictUnknownValuesSource interface {
+	StrictUnknownValues() bool
+}
+
+// unknownValues collects the values that weren't used by any of the fields
+// from all sources that are able to detect them, split into errors and
+// warnings, depending on the strictness of the manager and the sources.
+func (m *Manager) unknownValues() (errs, warnings []error) {
+	for _, s := range m.sources {
+		withUnknown, ok := s.(SourceWithUnknownValues)
+		if !ok {
+			continue
+		}
+		strictSource, ok := s.(strictUnknownValuesSource)
+		if m.strictUnknownValues || (ok && strictSource.StrictUnknownValues()) {
+			errs = append(errs, withUnknown.UnknownValues()...)
+		} else {
+			warnings = append(warnings, withUnknown.UnknownValues()...)
+		}
+	}
+	return errs, warnings
+}
+
+func (m *Manager) validateFields() []error {
+	var errs []error
 	for _, f := range m.fields {
 		fieldErr := f.Validate()
 		if fieldErr != nil {
 			errs = append(errs, fieldErr)
 		}
 	}
-
-	return consolidateErrorMessage(errs, "Validation errors: ")
+	return errs
 }
 
 func consolidateErrorMessage(errList []error, title string) error {
@@ -158,3 +206,13 @@ func WithDefaultSourceOfFieldNames(source Source) ManagerOption {
 		m.defaultSourceOfFieldNames = source
 	}
 }
+
+// WithStrictUnknownValues makes Consolidate() return an error if any of the
+// sources has values that weren't used by any field, e.g. a mistyped JSON key.
+// By default, they are only returned by Manager.Warnings(), unless the source
+// itself requires them to be errors, like SourceCLI does for unknown flags.
+func WithStrictUnknownValues() ManagerOption {
+	return func(m *Manager) {
+		m.strictUnknownValues = true
+	}
+}
@@ -0,0 +1,141 @@
+package croconf
+
+import "strings"
+
+// DocumentSource is implemented by the sources for structured config documents,
+// where values can be nested in objects, i.e. SourceJSON, SourceYAML,
+// SourceTOML, SourceINI, SourceLayeredJSON and SourceGoMap.
+type DocumentSource interface {
+	Source
+	FromPath(path ...string) DocumentValueBinder
+}
+
+// EnvSource is implemented by the sources of environment variables, i.e.
+// SourceEnvVars, which is also used for .env files by NewDotEnvSource().
+type EnvSource interface {
+	Source
+	FromVar(name string) NamedValueBinder
+}
+
+// CLISource is implemented by the sources of CLI flags, i.e. SourceCLI.
+type CLISource interface {
+	Source
+	FromFlag(name, shorthand string) NamedValueBinder
+}
+
+var (
+	_ DocumentSource = &SourceJSON{}
+	_ DocumentSource = &SourceYAML{}
+	_ DocumentSource = &SourceTOML{}
+	_ DocumentSource = &SourceINI{}
+	_ DocumentSource = &SourceLayeredJSON{}
+	_ DocumentSource = &SourceGoMap{}
+	_ EnvSource      = &SourceEnvVars{}
+	_ CLISource      = &SourceCLI{}
+)
+
+// NamingConvention derives the names of a config value in all of the usual
+// sources from a single canonical name, so that it doesn't have to be written
+// more than once. The canonical name is the path of the value in the document
+// source, with its parts separated by dots, e.g. for "dns.ttl" the derived
+// names will be:
+//   - the ttl property of the dns object in the document, e.g. JSON or YAML
+//   - the DNS_TTL environment variable, after any prefix of the env source,
+//     e.g. K6_DNS_TTL for a source created with WithPrefix("K6_")
+//   - the --dns-ttl CLI flag
+//
+// camelCase parts are split into words, e.g. "userAgent" becomes USER_AGENT
+// and --user-agent. Any of the sources can be nil, if its binders are not used.
+type NamingConvention struct {
+	doc DocumentSource
+	env EnvSource
+	cli CLISource
+}
+
+func NewNamingConvention(doc DocumentSource, env EnvSource, cli CLISource) *NamingConvention {
+	return &NamingConvention{doc: doc, env: env, cli: cli}
+}
+
+// Name returns the derived names for the given canonical name. The options can
+// override the names for specific sources.
+func (nc *NamingConvention) Name(canonical string, options ...NameOption) *Name {
+	parts := strings.Split(canonical, ".")
+	envParts := make([]string, len(parts))
+	for i, part := range parts {
+		envParts[i] = toScreamingSnakeCase(part)
+	}
+	envName := strings.Join(envParts, "_")
+
+	n := &Name{
+		nc:       nc,
+		jsonPath: parts,
+		envName:  envName,
+		cliName:  strings.ToLower(strings.ReplaceAll(envName, "_", "-")),
+	}
+	for _, opt := range options {
+		opt(n)
+	}
+	return n
+}
+
+// Name has the names of a single config value in the sources of a
+// NamingConvention. Its methods return the binders for each source.
+type Name struct {
+	nc *NamingConvention
+
+	jsonPath     []string
+	envName      string
+	cliName      string
+	cliShorthand string
+}
+
+type NameOption func(*Name)
+
+// WithJSONPath overrides the path of the value in the document source, e.g.
+// WithJSONPath("dns", "ttl") for the ttl property of the dns object. An empty
+// path is ignored, so the one derived from the canonical name is kept.
+func WithJSONPath(path ...string) NameOption {
+	return func(n *Name) {
+		if len(path) == 0 {
+			return
+		}
+		n.jsonPath = path
+	}
+}
+
+// WithEnvName overrides the name of the environment variable. Any prefix of
+// the source is still added to it.
+func WithEnvName(name string) NameOption {
+	return func(n *Name) {
+		n.envName = name
+	}
+}
+
+// WithCLIName overrides the long name of the CLI flag, without the dashes.
+func WithCLIName(name string) NameOption {
+	return func(n *Name) {
+		n.cliName = name
+	}
+}
+
+// WithCLIShorthand adds a single-letter alias for the CLI flag, e.g. "u" for
+// -u, since these can't be derived automatically.
+func WithCLIShorthand(shorthand string) NameOption {
+	return func(n *Name) {
+		n.cliShorthand = shorthand
+	}
+}
+
+// JSON returns the binder for the document source. Despite the name, it can
+// be any DocumentSource, e.g. YAML or TOML.
+func (n *Name) JSON() DocumentValueBinder {
+	return n.nc.doc.FromPath(n.jsonPath...)
+}
+
+func (n *Name) Env() NamedValueBinder {
+	return n.nc.env.FromVar(n.envName)
+}
+
+func (n *Name) CLI() NamedValueBinder {
+	return n.nc.cli.FromFlag(n.cliName, n.cliShorthand)
+}
@@ -0,0 +1,132 @@
+package croconf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNamingConvention(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		canonical string
+		options   []NameOption
+		expJSON   string
+		expEnv    string
+		expCLI    string
+	}{
+		{canonical: "vus", expJSON: "vus", expEnv: "K6_VUS", expCLI: "--vus"},
+		{canonical: "userAgent", expJSON: "userAgent", expEnv: "K6_USER_AGENT", expCLI: "--user-agent"},
+		{canonical: "dns.ttl", expJSON: "dns.ttl", expEnv: "K6_DNS_TTL", expCLI: "--dns-ttl"},
+		{canonical: "httpDebug.fullBody", expJSON: "httpDebug.fullBody", expEnv: "K6_HTTP_DEBUG_FULL_BODY", expCLI: "--http-debug-full-body"},
+		{
+			canonical: "vus",
+			options:   []NameOption{WithCLIShorthand("u")},
+			expJSON:   "vus", expEnv: "K6_VUS", expCLI: "--vus / -u",
+		},
+		{
+			canonical: "dns.server",
+			options:   []NameOption{WithJSONPath("dnsServer"), WithEnvName("DNS"), WithCLIName("dns")},
+			expJSON:   "dnsServer", expEnv: "K6_DNS", expCLI: "--dns",
+		},
+		{
+			canonical: "dns.ttl",
+			options:   []NameOption{WithJSONPath()},
+			expJSON:   "dns.ttl", expEnv: "K6_DNS_TTL", expCLI: "--dns-ttl",
+		},
+	}
+
+	names := NewNamingConvention(
+		NewJSONSource(nil), NewSourceFromEnv(nil, WithPrefix("K6_")), NewSourceFromCLIFlags(nil),
+	)
+	for _, tc := range testCases {
+		n := names.Name(tc.canonical, tc.options...)
+		var s string
+		if name := n.JSON().BindStringValueTo(&s).(BindingFromSource).BoundName(); name != tc.expJSON {
+			t.Errorf("%s: expected JSON name %s, got %s", tc.canonical, tc.expJSON, name)
+		}
+		if name := n.Env().BindStringValueTo(&s).(BindingFromSource).BoundName(); name != tc.expEnv {
+			t.Errorf("%s: expected env name %s, got %s", tc.canonical, tc.expEnv, name)
+		}
+		if name := n.CLI().BindStringValueTo(&s).(BindingFromSource).BoundName(); name != tc.expCLI {
+			t.Errorf("%s: expected CLI name %s, got %s", tc.canonical, tc.expCLI, name)
+		}
+	}
+}
+
+func TestNamingConventionConsolidate(t *testing.T) {
+	t.Parallel()
+
+	json := NewJSONSource([]byte(`{"userAgent": "json", "dns": {"ttl": "1m", "server": "1.1.1.1"}}`))
+	env := NewSourceFromEnv([]string{"K6_DNS_TTL=2m"}, WithPrefix("K6_"))
+	cli := NewSourceFromCLIFlags([]string{"--user-agent", "cli"})
+	names := NewNamingConvention(json, env, cli)
+
+	var userAgent, server string
+	var ttl time.Duration
+	cm := NewManager(WithStrictUnknownValues())
+	for _, f := range []struct {
+		canonical string
+		field     func(n *Name) Field
+	}{
+		{"userAgent", func(n *Name) Field { return NewStringField(&userAgent, n.JSON(), n.Env(), n.CLI()) }},
+		{"dns.ttl", func(n *Name) Field { return NewDurationField(&ttl, n.JSON(), n.Env(), n.CLI()) }},
+		{"dns.server", func(n *Name) Field { return NewStringField(&server, n.JSON(), n.Env(), n.CLI()) }},
+	} {
+		cm.AddField(f.field(names.Name(f.canonical)))
+	}
+
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+	if userAgent != "cli" || ttl != 2*time.Minute || server != "1.1.1.1" {
+		t.Errorf("unexpected values %q %s %q", userAgent, ttl, server)
+	}
+}
+
+func TestNamingConventionSources(t *testing.T) {
+	t.Parallel()
+
+	yaml := NewYAMLSource([]byte("userAgent: yaml\ndns:\n  ttl: 1m\n  server: 1.1.1.1\n"))
+	toml := NewTOMLSource([]byte("userAgent = \"toml\"\n[dns]\nttl = \"1m\"\nserver = \"1.1.1.1\"\n"))
+	layered := NewLayeredJSONSource([]JSONDocument{
+		{Name: "base.json", Data: []byte(`{"userAgent": "layered", "dns": {"ttl": "1m", "server": "8.8.8.8"}}`)},
+		{Name: "override.json", Data: []byte(`{"dns": {"server": "1.1.1.1"}}`)},
+	})
+	goMap, err := NewGoMapSource(map[string]interface{}{
+		"userAgent": "go map",
+		"dns":       map[string]interface{}{"ttl": "1m", "server": "1.1.1.1"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	testCases := []struct {
+		doc          DocumentSource
+		expUserAgent string
+	}{
+		{yaml, "yaml"},
+		{toml, "toml"},
+		{layered, "layered"},
+		{goMap, "go map"},
+	}
+	for _, tc := range testCases {
+		names := NewNamingConvention(
+			tc.doc, NewDotEnvSource([]byte("DNS_TTL=2m\n")), NewSourceFromCLIFlags(nil),
+		)
+		var userAgent, server string
+		var ttl time.Duration
+		cm := NewManager(WithStrictUnknownValues())
+		userAgentName, ttlName, serverName := names.Name("userAgent"), names.Name("dns.ttl"), names.Name("dns.server")
+		cm.AddField(NewStringField(&userAgent, userAgentName.JSON(), userAgentName.Env(), userAgentName.CLI()))
+		cm.AddField(NewDurationField(&ttl, ttlName.JSON(), ttlName.Env(), ttlName.CLI()))
+		cm.AddField(NewStringField(&server, serverName.JSON(), serverName.Env(), serverName.CLI()))
+
+		if err := cm.Consolidate(); err != nil {
+			t.Fatalf("%s: unexpected consolidation error %s", tc.doc.GetName(), err)
+		}
+		if userAgent != tc.expUserAgent || ttl != 2*time.Minute || server != "1.1.1.1" {
+			t.Errorf("%s: unexpected values %q %s %q", tc.doc.GetName(), userAgent, ttl, server)
+		}
+	}
+}
@@ -3,7 +3,10 @@ package croconf
 import (
 	"encoding"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"go.k6.io/croconf/flag"
 )
@@ -13,13 +16,65 @@ type SourceCLI struct {
 
 	parser *flag.Parser
 	fs     *flag.Set
+
+	// knownNames has the long and short names of all bindings that were
+	// applied since the source was initialized, i.e. the ones of the fields
+	// of the manager that is being consolidated
+	knownNames             map[string]struct{}
+	passthroughPrefixes    []string
+	unknownFlagsAsWarnings bool
+	strictBoolConflicts    bool
+}
+
+type CLISourceOption func(*SourceCLI)
+
+// WithPassthroughAfterDoubleDash stops the parsing of the flags at the first
+// -- argument. The arguments after it are returned by Passthrough(), so they
+// can be passed to other programs, and they are never reported as unknown.
+func WithPassthroughAfterDoubleDash() CLISourceOption {
+	return func(sc *SourceCLI) {
+		sc.parser.StopAtDoubleDash()
+	}
+}
+
+// WithPassthroughFlagPrefixes allows unknown flags that start with any of the
+// given prefixes, e.g. "x-" for --x-something. Since their types are unknown,
+// they should be given in the --name=value form, otherwise they will take the
+// next argument as their value.
+func WithPassthroughFlagPrefixes(prefixes ...string) CLISourceOption {
+	return func(sc *SourceCLI) {
+		sc.passthroughPrefixes = append(sc.passthroughPrefixes, prefixes...)
+	}
+}
+
+// WithUnknownFlagsAsWarnings makes the unknown flags be returned by
+// Manager.Warnings(), instead of failing the consolidation. That is useful if
+// not all of the flags are known during the first consolidation, e.g. when
+// every sub-command has its own flags.
+func WithUnknownFlagsAsWarnings() CLISourceOption {
+	return func(sc *SourceCLI) {
+		sc.unknownFlagsAsWarnings = true
+	}
 }
 
-func NewSourceFromCLIFlags(flags []string) *SourceCLI {
-	return &SourceCLI{
+// WithStrictBoolFlagConflicts makes the initialization fail if a boolean flag
+// is both set and negated, e.g. with --throw --no-throw. By default, the last
+// one of them wins.
+func WithStrictBoolFlagConflicts() CLISourceOption {
+	return func(sc *SourceCLI) {
+		sc.strictBoolConflicts = true
+	}
+}
+
+func NewSourceFromCLIFlags(flags []string, options ...CLISourceOption) *SourceCLI {
+	sc := &SourceCLI{
 		flags:  flags,
 		parser: flag.NewParser(),
 	}
+	for _, opt := range options {
+		opt(sc)
+	}
+	return sc
 }
 
 func (sc *SourceCLI) Initialize() error {
@@ -28,13 +83,79 @@ func (sc *SourceCLI) Initialize() error {
 		return err
 	}
 	sc.fs = fs
-	return nil
+	// The same source can be used by multiple managers, e.g. for sub-commands,
+	// so only the flags of the one that is consolidated now are known
+	sc.knownNames = make(map[string]struct{})
+
+	conflicts := fs.Conflicts()
+	if !sc.strictBoolConflicts || len(conflicts) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(conflicts))
+	for _, name := range conflicts {
+		msgs = append(msgs, fmt.Sprintf("--%s and --no-%s", name, name))
+	}
+	return fmt.Errorf("conflicting flags %s", strings.Join(msgs, ", "))
 }
 
 func (sc *SourceCLI) GetName() string {
 	return "CLI flags" // TODO
 }
 
+// Passthrough returns the arguments after the first --, when the source was
+// created with WithPassthroughAfterDoubleDash().
+func (sc *SourceCLI) Passthrough() []string {
+	if sc.fs == nil {
+		return nil
+	}
+	return sc.fs.Passthrough()
+}
+
+// UnknownValues returns errors for all given flags that don't have a binder.
+func (sc *SourceCLI) UnknownValues() []error {
+	known := make([]string, 0, len(sc.knownNames))
+	for name := range sc.knownNames {
+		known = append(known, name)
+	}
+	sort.Strings(known)
+
+	var errs []error
+	for _, flagName := range sc.fs.Names() {
+		name := strings.TrimLeft(flagName, "-")
+		if _, ok := sc.knownNames[name]; ok || sc.isPassthrough(name) {
+			continue
+		}
+		suggestion := closestName(name, known)
+		if suggestion != "" {
+			suggestion = cliFlagName(suggestion)
+		}
+		errs = append(errs, NewUnknownValueError("", "flag", flagName, suggestion))
+	}
+	return errs
+}
+
+// StrictUnknownValues makes the Manager fail the consolidation if there are any
+// unknown flags, unless WithUnknownFlagsAsWarnings() was used.
+func (sc *SourceCLI) StrictUnknownValues() bool {
+	return !sc.unknownFlagsAsWarnings
+}
+
+func (sc *SourceCLI) isPassthrough(name string) bool {
+	for _, prefix := range sc.passthroughPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func cliFlagName(name string) string {
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
 func (sc *SourceCLI) FromName(name string) *cliBinder {
 	return &cliBinder{source: sc, longhand: name}
 }
@@ -47,6 +168,12 @@ func (sc *SourceCLI) FromNameAndShorthand(name, shorthand string) *cliBinder {
 	}
 }
 
+// FromFlag is the same as FromNameAndShorthand, for the NamingConvention CLI
+// sources.
+func (sc *SourceCLI) FromFlag(name, shorthand string) NamedValueBinder {
+	return sc.FromNameAndShorthand(name, shorthand)
+}
+
 func (sc *SourceCLI) FromPositionalArg(position int) *cliBinder {
 	return &cliBinder{source: sc, position: position}
 }
@@ -56,6 +183,7 @@ type cliBinder struct {
 	shorthand string
 	longhand  string
 	position  int
+	negatable bool // for boolean flags, which also have a --no-<name> form
 
 	// lookupfn defines a custom lookup logic
 	lookupfn func() (string, error)
@@ -66,10 +194,37 @@ func (cb *cliBinder) boundName() string {
 	if cb.position > 0 {
 		return fmt.Sprintf("argument #%d", cb.position)
 	}
+	name := "--" + cb.longhand
 	if cb.shorthand != "" {
-		return fmt.Sprintf("--%s / -%s", cb.shorthand, cb.longhand)
+		name += " / -" + cb.shorthand
+	}
+	return name
+}
+
+// usage is the bound name, together with the --no-<name> negation of boolean
+// flags, since it's not a separate flag with its own binding.
+func (cb *cliBinder) usage() string {
+	if cb.negatable {
+		return cb.boundName() + " / --no-" + cb.longhand
+	}
+	return cb.boundName()
+}
+
+// markKnown records the names of the binder as known to the source, so they
+// aren't reported as unknown flags.
+func (cb *cliBinder) markKnown() {
+	known := cb.source.knownNames
+	if known == nil || cb.position > 0 {
+		return
+	}
+	for _, name := range []string{cb.longhand, cb.shorthand} {
+		if name != "" {
+			known[name] = struct{}{}
+		}
+	}
+	if cb.negatable {
+		known["no-"+cb.longhand] = struct{}{}
 	}
-	return fmt.Sprintf("--%s", cb.longhand)
 }
 
 func (cb *cliBinder) newBinding(apply func() error) *cliBinding {
@@ -112,7 +267,16 @@ func (cb *cliBinder) textValueHelper(callback func(string) error) Binding {
 	})
 }
 
+// registerValue registers the flag with the parser as one that needs a value,
+// so it's not mistaken for the --no-<name> negation of a boolean flag.
+func (cb *cliBinder) registerValue() {
+	if cb.lookupfn == nil && cb.position == 0 {
+		cb.source.parser.RegisterValue(cb.longhand, cb.shorthand)
+	}
+}
+
 func (cb *cliBinder) BindStringValueTo(dest *string) Binding {
+	cb.registerValue()
 	return cb.textValueHelper(func(s string) error {
 		*dest = s
 		return nil
@@ -120,12 +284,14 @@ func (cb *cliBinder) BindStringValueTo(dest *string) Binding {
 }
 
 func (cb *cliBinder) BindTextBasedValueTo(dest encoding.TextUnmarshaler) Binding {
+	cb.registerValue()
 	return cb.textValueHelper(func(s string) error {
 		return dest.UnmarshalText([]byte(s))
 	})
 }
 
 func (cb *cliBinder) BindIntValueTo(dest *int64) Binding {
+	cb.registerValue()
 	return cb.newBinding(func() error {
 		v, err := cb.lookup()
 		if err != nil {
@@ -141,6 +307,7 @@ func (cb *cliBinder) BindIntValueTo(dest *int64) Binding {
 }
 
 func (cb *cliBinder) BindUintValueTo(dest *uint64) Binding {
+	cb.registerValue()
 	return cb.newBinding(func() error {
 		v, err := cb.lookup()
 		if err != nil {
@@ -156,6 +323,7 @@ func (cb *cliBinder) BindUintValueTo(dest *uint64) Binding {
 }
 
 func (cb *cliBinder) BindFloatValueTo(dest *float64) Binding {
+	cb.registerValue()
 	return cb.newBinding(func() error {
 		v, err := cb.lookup()
 		if err != nil {
@@ -170,8 +338,28 @@ func (cb *cliBinder) BindFloatValueTo(dest *float64) Binding {
 	})
 }
 
+func (cb *cliBinder) BindDurationValueTo(dest *time.Duration, bareNumberUnit time.Duration) Binding {
+	cb.registerValue()
+	return cb.newBinding(func() error {
+		v, err := cb.lookup()
+		if err != nil {
+			return NewBindFieldMissingError(cb.source.GetName(), cb.boundName())
+		}
+		val, bindErr := parseDuration(v, bareNumberUnit)
+		if bindErr != nil {
+			return bindErr.withFuncName("BindDurationValue")
+		}
+		*dest = val
+		return nil
+	})
+}
+
 func (cb *cliBinder) BindBoolValueTo(dest *bool) Binding {
-	cb.source.parser.RegisterUnary(cb.longhand, cb.shorthand)
+	if cb.lookupfn == nil && cb.position == 0 {
+		// Elements of slices, e.g. from NewBoolSliceField, still need values
+		cb.source.parser.RegisterUnary(cb.longhand, cb.shorthand)
+		cb.negatable = len(cb.longhand) > 1
+	}
 	return cb.textValueHelper(func(v string) error {
 		b, err := strconv.ParseBool(v)
 		if err != nil {
@@ -206,6 +394,36 @@ func (cb *cliBinder) BindArrayValueTo(length *int, element *func(int) LazySingle
 	})
 }
 
+func (cb *cliBinder) BindMapValueTo(keys *[]string, element *func(string) LazySingleValueBinder) Binding {
+	cb.source.parser.RegisterSlice(cb.longhand, cb.shorthand)
+	return cb.newBinding(func() error {
+		opts := cb.source.fs.Options(cb.longhand, cb.shorthand)
+		if len(opts) < 1 {
+			return ErrorMissing
+		}
+
+		mapKeys, values, err := parseKeyValuePairs(cb.boundName(), opts)
+		if err != nil {
+			return err
+		}
+
+		*keys = mapKeys
+		*element = func(key string) LazySingleValueBinder {
+			cbcopy := *cb
+			cbcopy.lookupfn = func() (string, error) {
+				val, ok := values[key]
+				if !ok {
+					return "", fmt.Errorf("tried to access invalid key %s of %s", key, cbcopy.longhand)
+				}
+				return val, nil
+			}
+			return &cbcopy
+		}
+
+		return nil
+	})
+}
+
 type cliBinding struct {
 	binder *cliBinder
 	apply  func() error
@@ -214,9 +432,11 @@ type cliBinding struct {
 var _ interface {
 	Binding
 	BindingFromSource
+	BindingWithUsage
 } = &cliBinding{}
 
 func (cb *cliBinding) Apply() error {
+	cb.binder.markKnown()
 	return cb.apply()
 }
 
@@ -227,3 +447,7 @@ func (cb *cliBinding) Source() Source {
 func (cb *cliBinding) BoundName() string {
 	return cb.binder.boundName()
 }
+
+func (cb *cliBinding) Usage() string {
+	return cb.binder.usage()
+}
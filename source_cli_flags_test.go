@@ -47,3 +47,181 @@ func TestCLIBinding_BindStringValueTo(t *testing.T) {
 		}
 	}
 }
+
+func TestCLIUnknownFlags(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		args    []string
+		options []CLISourceOption
+		expErr  string
+	}{
+		{args: []string{"--vus", "10", "-u", "2", "--tag", "a"}},
+		{
+			args:   []string{"run", "--vsu", "10", "--tga=a", "-x"},
+			expErr: "Unknown config values: \n\t- unknown flag --vsu, did you mean --vus?\n\t- unknown flag --tga, did you mean --tag?\n\t- unknown flag -x",
+		},
+		{
+			args:    []string{"--vus", "10", "--x-foo=bar", "--", "--vsu", "10"},
+			options: []CLISourceOption{WithPassthroughAfterDoubleDash(), WithPassthroughFlagPrefixes("x-")},
+		},
+		{
+			args:   []string{"--vus", "10", "--x-foo=bar", "--", "--vsu", "10"},
+			expErr: "Unknown config values: \n\t- unknown flag --x-foo\n\t- unknown flag --vsu, did you mean --vus?",
+		},
+	}
+
+	for _, tc := range testCases {
+		var vus int64
+		var tags []string
+		src := NewSourceFromCLIFlags(tc.args, tc.options...)
+		cm := NewManager()
+		cm.AddField(NewInt64Field(&vus, src.FromNameAndShorthand("vus", "u")))
+		cm.AddField(NewStringSliceField(&tags, src.FromName("tag")))
+
+		err := cm.Consolidate()
+		if (tc.expErr == "" && err != nil) || (tc.expErr != "" && (err == nil || err.Error() != tc.expErr)) {
+			t.Errorf("%v: expected error '%s', got '%s'", tc.args, tc.expErr, err)
+		}
+	}
+
+	src := NewSourceFromCLIFlags(
+		[]string{"--vsu", "10", "--", "script.js"},
+		WithUnknownFlagsAsWarnings(), WithPassthroughAfterDoubleDash(),
+	)
+	var vus int64
+	cm := NewManager()
+	cm.AddField(NewInt64Field(&vus, src.FromName("vus")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+	if len(cm.Warnings()) != 1 || cm.Warnings()[0].Error() != "unknown flag --vsu, did you mean --vus?" {
+		t.Errorf("unexpected warnings %q", cm.Warnings())
+	}
+	if p := src.Passthrough(); len(p) != 1 || p[0] != "script.js" {
+		t.Errorf("unexpected passthrough arguments %q", p)
+	}
+	if name := src.FromNameAndShorthand("vus", "u").boundName(); name != "--vus / -u" {
+		t.Errorf("unexpected bound name %s", name)
+	}
+
+	// the flags of other managers that use the same source, e.g. for other
+	// sub-commands, aren't known
+	src = NewSourceFromCLIFlags([]string{"--vus", "10", "--tag", "a"}, WithUnknownFlagsAsWarnings())
+	var tags []string
+	cm = NewManager()
+	cm.AddField(NewInt64Field(&vus, src.FromName("vus")))
+	cm.AddField(NewStringSliceField(&tags, src.FromName("tag")))
+	if err := cm.Consolidate(); err != nil || len(cm.Warnings()) != 0 {
+		t.Errorf("unexpected consolidation error %s or warnings %q", err, cm.Warnings())
+	}
+	cm = NewManager()
+	cm.AddField(NewInt64Field(&vus, src.FromName("vus")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+	if len(cm.Warnings()) != 1 || cm.Warnings()[0].Error() != "unknown flag --tag" {
+		t.Errorf("unexpected warnings %q", cm.Warnings())
+	}
+}
+
+func TestCLIBoolNegation(t *testing.T) {
+	t.Parallel()
+	strict := []CLISourceOption{WithStrictBoolFlagConflicts()}
+	testCases := []struct {
+		args    []string
+		options []CLISourceOption
+		exp     bool
+		expErr  string
+	}{
+		{args: []string{"--no-throw"}, exp: false},
+		{args: []string{"--no-throw", "-w"}, exp: true},
+		{args: []string{"-w", "--no-throw"}, exp: false},
+		{args: []string{"--no-throw", "--throw"}, exp: true},
+		{args: []string{"--throw=false", "--no-throw"}, options: strict, exp: false},
+		{args: []string{"--no-throw", "--throw=false"}, options: strict, exp: false},
+		{args: []string{"--no-throw=true"}, exp: false},
+		{args: []string{"--no-throw=false"}, exp: true},
+		{args: []string{"--no-throw=false", "--throw"}, options: strict, exp: true},
+		{args: []string{"--no-throw=foo"}, expErr: "Config errors: \n\t- invalid value 'foo' for --no-throw, expected a boolean"},
+		{
+			args: []string{"--no-throw", "-w"}, options: strict,
+			expErr: "Config errors: \n\t- conflicting flags --throw and --no-throw",
+		},
+		{
+			args: []string{"--throw", "--no-throw=1"}, options: strict,
+			expErr: "Config errors: \n\t- conflicting flags --throw and --no-throw",
+		},
+	}
+
+	for _, tc := range testCases {
+		// the default is always the opposite value, so it must come from the flags
+		var throw bool
+		src := NewSourceFromCLIFlags(tc.args, tc.options...)
+		cm := NewManager()
+		cm.AddField(NewBoolField(&throw, DefaultBoolValue(!tc.exp), src.FromNameAndShorthand("throw", "w")))
+
+		err := cm.Consolidate()
+		if tc.expErr != "" {
+			if err == nil || err.Error() != tc.expErr {
+				t.Errorf("%v: expected error '%s', got '%s'", tc.args, tc.expErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error %s", tc.args, err)
+		}
+		if throw != tc.exp {
+			t.Errorf("%v: expected %t, got %t", tc.args, tc.exp, throw)
+		}
+		if len(cm.Warnings()) != 0 {
+			t.Errorf("%v: unexpected warnings %q", tc.args, cm.Warnings())
+		}
+	}
+}
+
+func TestCLIBoolNegationCollision(t *testing.T) {
+	t.Parallel()
+	// a real --no-proxy flag takes priority over the negation of --proxy
+	for _, args := range [][]string{{"--no-proxy=localhost", "--proxy"}, {"--proxy", "--no-proxy", "localhost"}} {
+		var proxy bool
+		var noProxy string
+		src := NewSourceFromCLIFlags(args)
+		cm := NewManager()
+		cm.AddField(NewBoolField(&proxy, src.FromName("proxy")))
+		cm.AddField(NewStringField(&noProxy, src.FromName("no-proxy")))
+
+		if err := cm.Consolidate(); err != nil {
+			t.Errorf("%v: unexpected error %s", args, err)
+		}
+		if !proxy || noProxy != "localhost" {
+			t.Errorf("%v: unexpected values %t and %q", args, proxy, noProxy)
+		}
+	}
+}
+
+func TestCLIBoolBoundName(t *testing.T) {
+	t.Parallel()
+	var b bool
+	var s string
+	var typed testFlag
+	src := NewSourceFromCLIFlags(nil)
+	testCases := []struct {
+		binding  Binding
+		expName  string
+		expUsage string
+	}{
+		{src.FromNameAndShorthand("throw", "w").BindBoolValueTo(&b), "--throw / -w", "--throw / -w / --no-throw"},
+		{src.FromName("verbose").BindBoolValueTo(&b), "--verbose", "--verbose / --no-verbose"},
+		{NewTypedBoolField(&typed, src.FromName("quiet")).Bindings()[0], "--quiet", "--quiet / --no-quiet"},
+		{src.FromNameAndShorthand("user", "u").BindStringValueTo(&s), "--user / -u", "--user / -u"},
+	}
+	for _, tc := range testCases {
+		// the negation is only a part of the usage, not of the name in errors
+		if name := tc.binding.(BindingFromSource).BoundName(); name != tc.expName {
+			t.Errorf("expected bound name %s, got %s", tc.expName, name)
+		}
+		if usage := tc.binding.(BindingWithUsage).Usage(); usage != tc.expUsage {
+			t.Errorf("expected usage %s, got %s", tc.expUsage, usage)
+		}
+	}
+}
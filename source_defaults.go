@@ -2,6 +2,9 @@ package croconf
 
 import (
 	"encoding"
+	"sort"
+	"strconv"
+	"time"
 )
 
 const defaultsBoundName = "default"
@@ -25,9 +28,67 @@ func (dsv defaultStringValue) BindTextBasedValueTo(dest encoding.TextUnmarshaler
 	})
 }
 
+func (dsv defaultStringValue) BindIntValueTo(dest *int64) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		val, bindErr := parseInt(string(dsv))
+		if bindErr != nil {
+			return bindErr.withFuncName("BindIntValue")
+		}
+		*dest = val
+		return nil
+	})
+}
+
+func (dsv defaultStringValue) BindUintValueTo(dest *uint64) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		val, bindErr := parseUint(string(dsv))
+		if bindErr != nil {
+			return bindErr.withFuncName("BindUintValue")
+		}
+		*dest = val
+		return nil
+	})
+}
+
+func (dsv defaultStringValue) BindFloatValueTo(dest *float64) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		val, bindErr := parseFloat(string(dsv))
+		if bindErr != nil {
+			return bindErr.withFuncName("BindFloatValue")
+		}
+		*dest = val
+		return nil
+	})
+}
+
+func (dsv defaultStringValue) BindBoolValueTo(dest *bool) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		val, err := strconv.ParseBool(string(dsv))
+		if err != nil {
+			return NewBindValueError("BindBoolValue", string(dsv), err)
+		}
+		*dest = val
+		return nil
+	})
+}
+
+func (dsv defaultStringValue) BindDurationValueTo(dest *time.Duration, bareNumberUnit time.Duration) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		val, bindErr := parseDuration(string(dsv), bareNumberUnit)
+		if bindErr != nil {
+			return bindErr.withFuncName("BindDurationValue")
+		}
+		*dest = val
+		return nil
+	})
+}
+
+// DefaultStringValue can be used as the default value for fields of any type
+// that can be parsed from a string, e.g. DefaultStringValue("10") is a valid
+// default for both int and float fields.
 func DefaultStringValue(s string) interface {
-	StringValueBinder
-	TextBasedValueBinder
+	LazySingleValueBinder
+	DurationValueBinder
 } {
 	return defaultStringValue(s)
 }
@@ -51,6 +112,150 @@ func DefaultIntValue(i int64) interface {
 	return defaultIntValue(i)
 }
 
+type defaultUintValue uint64
+
+func (duv defaultUintValue) BindUintValueTo(dest *uint64) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		*dest = uint64(duv)
+		return nil
+	})
+}
+
+func DefaultUintValue(i uint64) interface {
+	UintValueBinder
+} {
+	return defaultUintValue(i)
+}
+
+type defaultFloatValue float64
+
+func (dfv defaultFloatValue) BindFloatValueTo(dest *float64) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		*dest = float64(dfv)
+		return nil
+	})
+}
+
+func DefaultFloatValue(f float64) interface {
+	FloatValueBinder
+} {
+	return defaultFloatValue(f)
+}
+
+type defaultBoolValue bool
+
+func (dbv defaultBoolValue) BindBoolValueTo(dest *bool) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		*dest = bool(dbv)
+		return nil
+	})
+}
+
+func DefaultBoolValue(b bool) interface {
+	BoolValueBinder
+} {
+	return defaultBoolValue(b)
+}
+
+type defaultDurationValue time.Duration
+
+func (ddv defaultDurationValue) BindDurationValueTo(dest *time.Duration, _ time.Duration) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		*dest = time.Duration(ddv)
+		return nil
+	})
+}
+
+func DefaultDurationValue(d time.Duration) interface {
+	DurationValueBinder
+} {
+	return defaultDurationValue(d)
+}
+
+// defaultSliceValue keeps the string representations of all elements, so that
+// every element can be a fully-featured defaultStringValue.
+type defaultSliceValue []string
+
+func (dsv defaultSliceValue) BindArrayValueTo(length *int, element *func(int) LazySingleValueBinder) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		*length = len(dsv)
+		*element = func(elNum int) LazySingleValueBinder {
+			return defaultStringValue(dsv[elNum])
+		}
+		return nil
+	})
+}
+
+func DefaultStringSliceValue(values ...string) interface {
+	ArrayValueBinder
+} {
+	return defaultSliceValue(values)
+}
+
+func DefaultIntSliceValue(values ...int64) interface {
+	ArrayValueBinder
+} {
+	res := make(defaultSliceValue, len(values))
+	for i, v := range values {
+		res[i] = strconv.FormatInt(v, 10)
+	}
+	return res
+}
+
+func DefaultUintSliceValue(values ...uint64) interface {
+	ArrayValueBinder
+} {
+	res := make(defaultSliceValue, len(values))
+	for i, v := range values {
+		res[i] = strconv.FormatUint(v, 10)
+	}
+	return res
+}
+
+func DefaultFloatSliceValue(values ...float64) interface {
+	ArrayValueBinder
+} {
+	res := make(defaultSliceValue, len(values))
+	for i, v := range values {
+		res[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return res
+}
+
+func DefaultBoolSliceValue(values ...bool) interface {
+	ArrayValueBinder
+} {
+	res := make(defaultSliceValue, len(values))
+	for i, v := range values {
+		res[i] = strconv.FormatBool(v)
+	}
+	return res
+}
+
+type defaultStringMapValue map[string]string
+
+func (dmv defaultStringMapValue) BindMapValueTo(keys *[]string, element *func(string) LazySingleValueBinder) Binding {
+	return NewCallbackBindingFromSource(nil, defaultsBoundName, func() error {
+		mapKeys := make([]string, 0, len(dmv))
+		for key := range dmv {
+			mapKeys = append(mapKeys, key)
+		}
+		sort.Strings(mapKeys)
+
+		*keys = mapKeys
+		*element = func(key string) LazySingleValueBinder {
+			return defaultStringValue(dmv[key])
+		}
+		return nil
+	})
+}
+
+func DefaultStringMapValue(values map[string]string) interface {
+	MapValueBinder
+} {
+	return defaultStringMapValue(values)
+}
+
 type DefaultCustomValue func()
 
 var _ interface {
@@ -67,5 +272,3 @@ func (dcv DefaultCustomValue) BindValue() Binding {
 		return nil
 	})
 }
-
-// TODO: sources for the rest of the types
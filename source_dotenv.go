@@ -0,0 +1,278 @@
+package croconf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const dotEnvDefaultName = ".env"
+
+type dotEnvConfig struct {
+	fileName string
+	environ  map[string]string
+}
+
+type DotEnvSourceOption func(*dotEnvConfig)
+
+// WithDotEnvFileName sets the file name that is used as the source name and in
+// the error messages, instead of the default ".env".
+func WithDotEnvFileName(fileName string) DotEnvSourceOption {
+	return func(c *dotEnvConfig) {
+		c.fileName = fileName
+	}
+}
+
+// WithDotEnvExpansionFrom allows ${VAR} references in the .env file to be
+// expanded with the given os.Environ()-style values, if VAR is not defined in
+// the file itself.
+func WithDotEnvExpansionFrom(environ []string) DotEnvSourceOption {
+	return func(c *dotEnvConfig) {
+		for _, kv := range environ {
+			k, v := parseEnvKeyValue(kv)
+			c.environ[k] = v
+		}
+	}
+}
+
+// NewDotEnvSource creates a source for the contents of .env files. It has the
+// same binders as the environment variables source, from NewSourceFromEnv().
+//
+// The supported syntax includes KEY=value and `export KEY=value` lines, # comments,
+// single-quoted literal values, double-quoted values with escape sequences,
+// multiline quoted values and ${OTHER}, $OTHER and ${OTHER:-default} expansion
+// in unquoted and double-quoted values.
+func NewDotEnvSource(data []byte, options ...DotEnvSourceOption) *SourceEnvVars {
+	conf := &dotEnvConfig{fileName: dotEnvDefaultName, environ: make(map[string]string)}
+	for _, opt := range options {
+		opt(conf)
+	}
+
+	sev := &SourceEnvVars{
+		env:       make(map[string]string),
+		name:      conf.fileName,
+		locations: make(map[string]string),
+	}
+	sev.init = func() error {
+		p := &dotEnvParser{s: string(data), lineStarts: []int{0}, conf: conf, source: sev}
+		for i := 0; i < len(p.s); i++ {
+			if p.s[i] == '\n' {
+				p.lineStarts = append(p.lineStarts, i+1)
+			}
+		}
+		return p.parse()
+	}
+	return sev
+}
+
+type dotEnvParser struct {
+	s          string
+	i          int
+	lineStarts []int
+	conf       *dotEnvConfig
+	source     *SourceEnvVars
+}
+
+func (p *dotEnvParser) position(offset int) (line, column int) {
+	line = sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > offset })
+	return line, offset - p.lineStarts[line-1] + 1
+}
+
+func (p *dotEnvParser) errorAt(offset int, format string, args ...interface{}) error {
+	line, column := p.position(offset)
+	return NewSourceParseError(p.conf.fileName, line, column, fmt.Errorf(format, args...))
+}
+
+func (p *dotEnvParser) skipSpaces() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+func (p *dotEnvParser) skipComment() {
+	if p.i < len(p.s) && p.s[p.i] == '#' {
+		for p.i < len(p.s) && p.s[p.i] != '\n' {
+			p.i++
+		}
+	}
+}
+
+func isEnvNameChar(c byte, first bool) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || (!first && (c >= '0' && c <= '9' || c == '.'))
+}
+
+func isValidEnvName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if !isEnvNameChar(name[i], i == 0) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+func (p *dotEnvParser) parse() error {
+	for {
+		for p.skipSpaces(); p.i < len(p.s) && strings.IndexByte("#\r\n", p.s[p.i]) >= 0; p.skipSpaces() {
+			p.skipComment()
+			if p.i < len(p.s) {
+				p.i++
+			}
+		}
+		if p.i >= len(p.s) {
+			return nil
+		}
+
+		start := p.i
+		if strings.HasPrefix(p.s[p.i:], "export ") || strings.HasPrefix(p.s[p.i:], "export\t") {
+			p.i += len("export")
+			p.skipSpaces()
+		}
+		nameStart := p.i
+		for p.i < len(p.s) && isEnvNameChar(p.s[p.i], p.i == nameStart) {
+			p.i++
+		}
+		name := p.s[nameStart:p.i]
+		if name == "" {
+			return p.errorAt(nameStart, "invalid variable name")
+		}
+		p.skipSpaces()
+		if p.i >= len(p.s) || p.s[p.i] != '=' {
+			return p.errorAt(p.i, "expected '=' after %s", name)
+		}
+		p.i++
+		p.skipSpaces()
+
+		value, err := p.parseValue()
+		if err != nil {
+			return err
+		}
+		p.skipSpaces()
+		p.skipComment()
+		if p.i < len(p.s) && p.s[p.i] != '\n' && p.s[p.i] != '\r' {
+			return p.errorAt(p.i, "unexpected content after the value of %s", name)
+		}
+
+		line, _ := p.position(start)
+		p.source.env[name] = value
+		p.source.locations[name] = fmt.Sprintf("%s:%d", p.conf.fileName, line)
+	}
+}
+
+func (p *dotEnvParser) parseValue() (string, error) {
+	start := p.i
+	if p.i >= len(p.s) {
+		return "", nil
+	}
+
+	switch p.s[p.i] {
+	case '\'':
+		end := strings.IndexByte(p.s[p.i+1:], '\'')
+		if end < 0 {
+			return "", p.errorAt(start, "unterminated quoted value")
+		}
+		p.i += end + 2
+		return p.s[start+1 : p.i-1], nil
+	case '"':
+		var sb strings.Builder
+		for p.i++; p.i < len(p.s); p.i++ {
+			switch c := p.s[p.i]; c {
+			case '"':
+				p.i++
+				return sb.String(), nil
+			case '\\':
+				if p.i+1 < len(p.s) {
+					p.i++
+					escaped := map[byte]string{'n': "\n", 't': "\t", 'r': "\r", '"': "\"", '\\': "\\", '$': "$"}
+					if s, ok := escaped[p.s[p.i]]; ok {
+						sb.WriteString(s)
+					} else {
+						sb.WriteByte('\\')
+						sb.WriteByte(p.s[p.i])
+					}
+				}
+			case '$':
+				expanded, err := p.expand()
+				if err != nil {
+					return "", err
+				}
+				sb.WriteString(expanded)
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return "", p.errorAt(start, "unterminated quoted value")
+	}
+
+	var sb strings.Builder
+	for ; p.i < len(p.s) && p.s[p.i] != '\n' && p.s[p.i] != '\r'; p.i++ {
+		c := p.s[p.i]
+		if c == '#' && p.i > 0 && (p.s[p.i-1] == ' ' || p.s[p.i-1] == '\t') {
+			break // an inline comment
+		}
+		if c != '$' {
+			sb.WriteByte(c)
+			continue
+		}
+		expanded, err := p.expand()
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(expanded)
+	}
+	return strings.TrimRight(sb.String(), " \t"), nil
+}
+
+// expand handles the variable reference at the current position, i.e. $NAME,
+// ${NAME}, ${NAME:-default} or ${NAME-default}. It leaves the position at the
+// last character of the reference.
+func (p *dotEnvParser) expand() (string, error) {
+	start := p.i
+	if p.i+1 >= len(p.s) || (p.s[p.i+1] != '{' && !isEnvNameChar(p.s[p.i+1], true)) {
+		return "$", nil
+	}
+
+	if p.s[p.i+1] != '{' {
+		p.i++
+		nameStart := p.i
+		for p.i < len(p.s) && isEnvNameChar(p.s[p.i], p.i == nameStart) && p.s[p.i] != '.' {
+			p.i++
+		}
+		name := p.s[nameStart:p.i]
+		p.i--
+		val, _ := p.lookup(name)
+		return val, nil
+	}
+
+	end := strings.IndexByte(p.s[p.i:], '}')
+	if end < 0 {
+		return "", p.errorAt(start, "unterminated variable reference")
+	}
+	p.i += end
+	ref := p.s[start+2 : p.i]
+
+	name, defaultVal, ifEmpty, hasDefault := ref, "", false, false
+	if idx := strings.Index(ref, ":-"); idx >= 0 {
+		name, defaultVal, ifEmpty, hasDefault = ref[:idx], ref[idx+2:], true, true
+	} else if idx := strings.IndexByte(ref, '-'); idx >= 0 {
+		name, defaultVal, hasDefault = ref[:idx], ref[idx+1:], true
+	}
+	if !isValidEnvName(name) {
+		return "", p.errorAt(start, "invalid variable reference ${%s}", ref)
+	}
+
+	val, ok := p.lookup(name)
+	if hasDefault && (!ok || (ifEmpty && val == "")) {
+		return defaultVal, nil
+	}
+	return val, nil
+}
+
+// lookup returns the value of previously defined variables in the file or, if
+// there are no such variables, the value from the configured environment.
+func (p *dotEnvParser) lookup(name string) (string, bool) {
+	if val, ok := p.source.env[name]; ok {
+		return val, true
+	}
+	val, ok := p.conf.environ[name]
+	return val, ok
+}
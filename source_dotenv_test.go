@@ -0,0 +1,98 @@
+package croconf
+
+import (
+	"testing"
+)
+
+func TestDotEnvParse(t *testing.T) {
+	t.Parallel()
+
+	dotEnv := `
+# comments and empty lines are ignored
+
+K6_VUS=10
+export K6_DURATION = 1m # inline comment
+K6_USER_AGENT="croconf \"test\"\tv1"
+K6_LITERAL='no $K6_VUS expansion \n'
+K6_MULTILINE="first line
+second line"
+K6_HOME=${HOME}/k6
+K6_TAGS=vus=${K6_VUS},os=$OS
+K6_DEFAULT=${MISSING:-fallback}
+K6_EMPTY=
+K6_HASH=foo#bar
+`
+	source := NewDotEnvSource([]byte(dotEnv), WithDotEnvExpansionFrom([]string{"HOME=/home/test", "OS=linux"}))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	expected := map[string]string{
+		"K6_VUS":        "10",
+		"K6_DURATION":   "1m",
+		"K6_USER_AGENT": "croconf \"test\"\tv1",
+		"K6_LITERAL":    `no $K6_VUS expansion \n`,
+		"K6_MULTILINE":  "first line\nsecond line",
+		"K6_HOME":       "/home/test/k6",
+		"K6_TAGS":       "vus=10,os=linux",
+		"K6_DEFAULT":    "fallback",
+		"K6_EMPTY":      "",
+		"K6_HASH":       "foo#bar",
+	}
+	for name, exp := range expected {
+		var val string
+		if err := source.From(name).BindStringValueTo(&val).Apply(); err != nil {
+			t.Errorf("unexpected error for %s: %s", name, err)
+		}
+		if val != exp {
+			t.Errorf("expected %s to be %q, got %q", name, exp, val)
+		}
+	}
+
+	var vus int64
+	if err := source.From("K6_VUS").BindIntValueTo(&vus).Apply(); err != nil || vus != 10 {
+		t.Errorf("expected 10 VUs, got %d (error: %s)", vus, err)
+	}
+
+	binding := source.From("K6_USER_AGENT").BindIntValueTo(&vus)
+	if bfs := binding.(BindingFromSource); bfs.Source() != source || bfs.Source().GetName() != ".env" {
+		t.Errorf("unexpected source %s", bfs.Source().GetName())
+	}
+	expErr := `.env:6: K6_USER_AGENT: BindIntValue: parsing "croconf \"test\"\tv1": invalid syntax`
+	if err := binding.Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	expErr = "field K6_MISSING is missing in config source .env"
+	if err := source.From("K6_MISSING").BindIntValueTo(&vus).Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
+
+func TestDotEnvParseErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		dotEnv string
+		expErr string
+	}{
+		{"A=1\n1A=2", "local.env:2:1: invalid variable name"},
+		{"A=1\nB", "local.env:2:2: expected '=' after B"},
+		{"A=\"foo\nB=2", "local.env:1:3: unterminated quoted value"},
+		{"A='foo", "local.env:1:3: unterminated quoted value"},
+		{"A=\"foo\" bar", "local.env:1:9: unexpected content after the value of A"},
+		{"A=${B", "local.env:1:3: unterminated variable reference"},
+		{"A=${B C}", "local.env:1:3: invalid variable reference ${B C}"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.dotEnv, func(t *testing.T) {
+			t.Parallel()
+			err := NewDotEnvSource([]byte(tc.dotEnv), WithDotEnvFileName("local.env")).Initialize()
+			if err == nil || err.Error() != tc.expErr {
+				t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+			}
+		})
+	}
+}
@@ -2,38 +2,116 @@ package croconf
 
 import (
 	"encoding"
+	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type SourceEnvVars struct {
-	env map[string]string
-	// TODO
+	env  map[string]string
+	name string
+	init func() error
+
+	// locations contains the file:line positions of the variables, for
+	// sources like .env files
+	locations map[string]string
+
+	prefix    string
+	requested map[string]bool // the full names of all looked up variables
+}
+
+type EnvSourceOption func(*SourceEnvVars)
+
+// WithPrefix makes all names that are given to From() relative to the prefix,
+// e.g. with the K6_ prefix, From("VUS") will use the K6_VUS variable. Any
+// variables with the prefix that weren't used by a field are reported as
+// unknown values by the Manager.
+func WithPrefix(prefix string) EnvSourceOption {
+	return func(sev *SourceEnvVars) {
+		sev.prefix = prefix
+	}
 }
 
-func NewSourceFromEnv(environ []string) *SourceEnvVars {
+func NewSourceFromEnv(environ []string, options ...EnvSourceOption) *SourceEnvVars {
 	env := make(map[string]string, len(environ))
 	for _, kv := range environ {
 		k, v := parseEnvKeyValue(kv)
 		env[k] = v
 	}
-	return &SourceEnvVars{env: env}
+	sev := &SourceEnvVars{env: env, name: "environment variables"}
+	for _, opt := range options {
+		opt(sev)
+	}
+	return sev
 }
 
 func (sev *SourceEnvVars) Initialize() error {
-	return nil // TODO? maybe prefix handling?
+	if sev.init == nil {
+		return nil
+	}
+	return sev.init()
+}
+
+// UnknownValues returns errors for all variables with the prefix from
+// WithPrefix() that weren't looked up by any binder. Without a prefix, there
+// is no way to know which variables are meant for us, so nothing is returned.
+func (sev *SourceEnvVars) UnknownValues() []error {
+	if sev.prefix == "" {
+		return nil
+	}
+
+	// the prefix is not considered when looking for similar names
+	known := make([]string, 0, len(sev.requested))
+	for name := range sev.requested {
+		if strings.HasPrefix(name, sev.prefix) {
+			known = append(known, strings.TrimPrefix(name, sev.prefix))
+		}
+	}
+	sort.Strings(known)
+	names := make([]string, 0, len(sev.env))
+	for name := range sev.env {
+		if strings.HasPrefix(name, sev.prefix) && !sev.requested[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	errs := make([]error, 0, len(names))
+	for _, name := range names {
+		suggestion := closestName(strings.TrimPrefix(name, sev.prefix), known)
+		if suggestion != "" {
+			suggestion = sev.prefix + suggestion
+		}
+		errs = append(errs, NewUnknownValueError(sev.locations[name], "environment variable", name, suggestion))
+	}
+	return errs
 }
 
 func (sev *SourceEnvVars) GetName() string {
-	return "environment variables" // TODO
+	return sev.name
 }
 
 func (sev *SourceEnvVars) From(name string) *envBinder {
+	return sev.fromFullName(sev.prefix + name)
+}
+
+// FromVar is the same as From, for the NamingConvention env sources.
+func (sev *SourceEnvVars) FromVar(name string) NamedValueBinder {
+	return sev.From(name)
+}
+
+func (sev *SourceEnvVars) fromFullName(name string) *envBinder {
 	return &envBinder{
 		source: sev,
 		name:   name,
 		lookup: func() (string, error) {
+			if sev.requested == nil {
+				sev.requested = make(map[string]bool)
+			}
+			sev.requested[name] = true
 			val, ok := sev.env[name]
 			if !ok {
 				return "", ErrorMissing // TODO: better error message, e.g. 'field %s is not present in %s'?
@@ -44,7 +122,7 @@ func (sev *SourceEnvVars) From(name string) *envBinder {
 }
 
 type envBinder struct {
-	source Source
+	source *SourceEnvVars
 	name   string
 	lookup func() (string, error)
 }
@@ -115,6 +193,21 @@ func (eb *envBinder) BindFloatValueTo(dest *float64) Binding {
 	})
 }
 
+func (eb *envBinder) BindDurationValueTo(dest *time.Duration, bareNumberUnit time.Duration) Binding {
+	return eb.newBinding(func() error {
+		strVal, err := eb.lookup()
+		if err != nil {
+			return NewBindFieldMissingError(eb.source.GetName(), eb.name)
+		}
+		val, bindErr := parseDuration(strVal, bareNumberUnit)
+		if bindErr != nil {
+			return bindErr.withFuncName("BindDurationValue")
+		}
+		*dest = val
+		return nil
+	})
+}
+
 func (eb *envBinder) BindBoolValueTo(dest *bool) Binding {
 	return eb.newBinding(func() error {
 		val, err := eb.lookup()
@@ -168,6 +261,93 @@ func (eb *envBinder) BindArrayValueTo(length *int, element *func(int) LazySingle
 	})
 }
 
+func (eb *envBinder) BindMapValueTo(keys *[]string, element *func(string) LazySingleValueBinder) Binding {
+	return eb.newBinding(func() error {
+		val, err := eb.lookup()
+		if err != nil {
+			return NewBindFieldMissingError(eb.source.GetName(), eb.name)
+		}
+
+		// TODO: figure out how to make the delimiters configurable
+		mapKeys, values, err := parseKeyValuePairs(eb.name, strings.Split(val, ","))
+		if err != nil {
+			return err
+		}
+
+		*keys = mapKeys
+		*element = func(key string) LazySingleValueBinder {
+			name := fmt.Sprintf("%s[%s]", eb.name, key)
+			return &envBinder{
+				source: eb.source,
+				name:   name,
+				lookup: func() (string, error) {
+					val, ok := values[key]
+					if !ok {
+						return "", NewBindFieldMissingError(eb.source.GetName(), name)
+					}
+					return val, nil
+				},
+			}
+		}
+		return nil
+	})
+}
+
+// BindObjectArrayValueTo binds indexed environment variables, e.g. for the
+// APP_TARGETS name, the properties of the first element will be in environment
+// variables like APP_TARGETS_0_URL, the second in APP_TARGETS_1_URL, etc.
+func (eb *envBinder) BindObjectArrayValueTo(length *int, element *func(int) ObjectValueBinder) Binding {
+	return eb.newBinding(func() error {
+		prefix := eb.name + "_"
+		indexes := make(map[int]struct{})
+		for key := range eb.source.env {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			rest := key[len(prefix):]
+			idxEnd := strings.IndexRune(rest, '_')
+			if idxEnd < 1 {
+				continue
+			}
+			if idx, err := strconv.Atoi(rest[:idxEnd]); err == nil && idx >= 0 {
+				indexes[idx] = struct{}{}
+			}
+		}
+		if len(indexes) == 0 {
+			return NewBindFieldMissingError(eb.source.GetName(), eb.name)
+		}
+
+		// The array ends at the first missing index
+		arrLength := 0
+		for _, ok := indexes[arrLength]; ok; _, ok = indexes[arrLength] {
+			arrLength++
+		}
+
+		*length = arrLength
+		*element = func(elNum int) ObjectValueBinder {
+			return &envObjectBinder{source: eb.source, name: fmt.Sprintf("%s_%d", eb.name, elNum)}
+		}
+		return nil
+	})
+}
+
+type envObjectBinder struct {
+	source *SourceEnvVars
+	name   string
+}
+
+// Property returns a binder for the given property of the object, converting
+// its name from camelCase, e.g. for a "maxRetries" property of an object with
+// the APP_TARGETS_0 name, the environment variable APP_TARGETS_0_MAX_RETRIES
+// will be used.
+func (eob *envObjectBinder) propertyPrefix() string {
+	return eob.name + "_"
+}
+
+func (eob *envObjectBinder) Property(name string) PropertyValueBinder {
+	return eob.source.fromFullName(eob.name + "_" + toScreamingSnakeCase(name))
+}
+
 func parseEnvKeyValue(kv string) (string, string) {
 	if idx := strings.IndexRune(kv, '='); idx != -1 {
 		return kv[:idx], kv[idx+1:]
@@ -186,7 +366,16 @@ var _ interface {
 } = &envBinding{}
 
 func (eb *envBinding) Apply() error {
-	return eb.apply()
+	err := eb.apply()
+	if err == nil {
+		return nil
+	}
+	var missingErr *BindFieldMissingError
+	if location, ok := eb.binder.source.locations[eb.binder.name]; ok &&
+		!errors.Is(err, ErrorMissing) && !errors.As(err, &missingErr) {
+		return NewSourceValueError(location, eb.binder.name, err)
+	}
+	return err
 }
 
 func (eb *envBinding) Source() Source {
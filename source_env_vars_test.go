@@ -2,6 +2,8 @@ package croconf
 
 import (
 	"math"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -142,3 +144,58 @@ func TestEnvVarsFloatValue(t *testing.T) {
 		t.Errorf("BindFloatValue: unexpected error message")
 	}
 }
+
+func TestEnvVarsPrefix(t *testing.T) {
+	t.Parallel()
+	environ := []string{
+		"K6_VUS=6", "K6_VU=10", "VUS=1", "K6_TARGETS_0_URL=a", "K6_TARGETS_0_MAX_RETIRES=2", "K6_FOO=bar", "HOME=/root",
+	}
+
+	newManager := func(options ...ManagerOption) (*Manager, *int64) {
+		source := NewSourceFromEnv(environ, WithPrefix("K6_"))
+		var vus int64
+		var targets []testTarget
+		cm := NewManager(options...)
+		cm.AddField(NewInt64Field(&vus, source.From("VUS")))
+		cm.AddField(NewStructSliceField(
+			&targets,
+			func(target *testTarget, el ObjectValueBinder, cm *Manager) {
+				cm.AddField(NewStringField(&target.URL, el.Property("url")))
+				cm.AddField(NewInt64Field(&target.MaxRetries, el.Property("maxRetries")))
+			},
+			source.From("TARGETS"),
+		))
+		return cm, &vus
+	}
+
+	expWarnings := []string{
+		"unknown environment variable K6_FOO",
+		"unknown environment variable K6_TARGETS_0_MAX_RETIRES, did you mean K6_TARGETS_0_MAX_RETRIES?",
+		"unknown environment variable K6_VU, did you mean K6_VUS?",
+	}
+
+	cm, vus := newManager()
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+	if *vus != 6 {
+		t.Errorf("expected vus to be 6, got %d", *vus)
+	}
+	warnings := make([]string, 0, len(cm.Warnings()))
+	for _, w := range cm.Warnings() {
+		warnings = append(warnings, w.Error())
+	}
+	if !reflect.DeepEqual(warnings, expWarnings) {
+		t.Errorf("expected warnings %q, got %q", expWarnings, warnings)
+	}
+
+	cm, _ = newManager(WithStrictUnknownValues())
+	expErr := "Unknown config values: \n\t- " + strings.Join(expWarnings, "\n\t- ")
+	if err := cm.Consolidate(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	if unknown := NewSourceFromEnv(environ).UnknownValues(); len(unknown) != 0 {
+		t.Errorf("expected no unknown values without a prefix, got %q", unknown)
+	}
+}
@@ -1,11 +1,207 @@
 package croconf
 
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// SourceGoMap is a config source for programmatically created values, e.g.
+// test fixtures or the result of another library decoding YAML or HCL. Nested
+// maps with string keys are objects, slices and arrays are arrays, and numbers
+// are converted between the Go int, uint and float kinds when they are bound,
+// as long as they don't overflow the destination.
 type SourceGoMap struct {
-	values map[string]interface{}
+	doc *treeDocument
 }
 
 func NewGoMapSource(values map[string]interface{}) (*SourceGoMap, error) {
-	return &SourceGoMap{values: values}, nil
+	sgm := &SourceGoMap{}
+	sgm.doc = &treeDocument{source: sgm}
+
+	root, err := goValueToTreeNode(reflect.ValueOf(values), "", make(map[goValueRef]struct{}))
+	if err != nil {
+		return nil, err
+	}
+	if root.kind == treeNull {
+		root = newTreeObject(0, 0)
+	}
+	sgm.doc.root = root
+	return sgm, nil
+}
+
+func (sgm *SourceGoMap) Initialize() error {
+	// The values are converted only once, so only the marks of the previous
+	// consolidation need to be cleared
+	sgm.doc.root.resetUsed()
+	return nil
+}
+
+func (sgm *SourceGoMap) GetName() string {
+	return "go map"
+}
+
+func (sgm *SourceGoMap) From(name string) *treeBinder {
+	return sgm.doc.from(name)
+}
+
+func (sgm *SourceGoMap) FromPath(path ...string) DocumentValueBinder {
+	return sgm.doc.fromPath(path)
+}
+
+// UnknownValues returns errors for all keys in the document that weren't used
+// by any of the fields.
+func (sgm *SourceGoMap) UnknownValues() []error {
+	return sgm.doc.UnknownValues()
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// goValueRef identifies a map or a slice, so that values which contain
+// themselves can be detected, the same way encoding/json does it.
+type goValueRef struct {
+	ptr uintptr
+	len int
+}
+
+// visit marks the given map or slice as being converted, until the returned
+// function is called. It returns an error if it is already being converted,
+// i.e. if it contains a reference to itself.
+func visit(visiting map[goValueRef]struct{}, val reflect.Value, name string) (func(), error) {
+	ref := goValueRef{ptr: val.Pointer(), len: val.Len()}
+	if _, ok := visiting[ref]; ok {
+		return nil, fmt.Errorf("invalid value for %s, it contains a reference to itself", name)
+	}
+	visiting[ref] = struct{}{}
+	return func() { delete(visiting, ref) }, nil
 }
 
-// TODO: implement something like https://github.com/mitchellh/mapstructure
+func goValueToTreeNode(val reflect.Value, name string, visiting map[goValueRef]struct{}) (*treeNode, error) {
+	for val.Kind() == reflect.Interface || val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return &treeNode{kind: treeNull}, nil
+		}
+		if val.Type().Implements(textMarshalerType) {
+			break
+		}
+		val = val.Elem()
+	}
+	if !val.IsValid() {
+		return &treeNode{kind: treeNull}, nil
+	}
+
+	node := &treeNode{kind: treeScalar}
+	switch {
+	case val.Type() == timeType:
+		node.value = val.Interface()
+		node.raw = val.Interface().(time.Time).Format(time.RFC3339Nano) //nolint:forcetypeassert
+		return node, nil
+	case val.Type() == durationType:
+		node.raw = time.Duration(val.Int()).String()
+		node.value = node.raw
+		return node, nil
+	case val.Type().Implements(textMarshalerType):
+		text, err := val.Interface().(encoding.TextMarshaler).MarshalText() //nolint:forcetypeassert
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", name, err)
+		}
+		node.value, node.raw = string(text), string(text)
+		return node, nil
+	}
+
+	switch val.Kind() { //nolint:exhaustive
+	case reflect.String:
+		node.value, node.raw = val.String(), val.String()
+	case reflect.Bool:
+		node.value, node.raw = val.Bool(), strconv.FormatBool(val.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		node.value, node.raw = val.Int(), strconv.FormatInt(val.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		node.value, node.raw = val.Uint(), strconv.FormatUint(val.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		// float32 values are converted through their shortest representation,
+		// so that e.g. float32(0.1) becomes 0.1 and not 0.10000000149011612
+		bitSize := val.Type().Bits()
+		node.raw = strconv.FormatFloat(val.Float(), 'g', -1, bitSize)
+		node.value, _ = strconv.ParseFloat(node.raw, 64)
+	case reflect.Slice, reflect.Array:
+		return goSliceToTreeNode(val, name, visiting)
+	case reflect.Map:
+		return goMapToTreeNode(val, name, visiting)
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s for %s", val.Type(), name)
+	}
+	return node, nil
+}
+
+func goSliceToTreeNode(val reflect.Value, name string, visiting map[goValueRef]struct{}) (*treeNode, error) {
+	if val.Kind() == reflect.Slice {
+		if val.IsNil() {
+			return &treeNode{kind: treeNull}, nil
+		}
+		done, err := visit(visiting, val, name)
+		if err != nil {
+			return nil, err
+		}
+		defer done()
+	}
+	node := &treeNode{kind: treeArray, elements: make([]*treeNode, val.Len())}
+	for i := 0; i < val.Len(); i++ {
+		el, err := goValueToTreeNode(val.Index(i), fmt.Sprintf("%s[%d]", name, i), visiting)
+		if err != nil {
+			return nil, err
+		}
+		node.elements[i] = el
+	}
+	return node, nil
+}
+
+func goMapToTreeNode(val reflect.Value, name string, visiting map[goValueRef]struct{}) (*treeNode, error) {
+	if val.IsNil() {
+		return &treeNode{kind: treeNull}, nil
+	}
+	done, err := visit(visiting, val, name)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	values := make(map[string]reflect.Value, val.Len())
+	keys := make([]string, 0, val.Len())
+	iter := val.MapRange()
+	for iter.Next() {
+		key := iter.Key()
+		if key.Kind() == reflect.Interface {
+			key = key.Elem()
+		}
+		if !key.IsValid() || key.Kind() != reflect.String {
+			return nil, fmt.Errorf(
+				"unsupported map key %#v for %s, only strings are supported", iter.Key().Interface(), name,
+			)
+		}
+		keys = append(keys, key.String())
+		values[key.String()] = iter.Value()
+	}
+	sort.Strings(keys) // Go maps are not ordered, but we want our results to be
+
+	node := newTreeObject(0, 0)
+	for _, key := range keys {
+		propName := key
+		if name != "" {
+			propName = name + "." + key
+		}
+		prop, err := goValueToTreeNode(values[key], propName, visiting)
+		if err != nil {
+			return nil, err
+		}
+		node.set(key, prop)
+	}
+	return node, nil
+}
@@ -0,0 +1,195 @@
+package croconf
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGoMapBindValues(t *testing.T) {
+	t.Parallel()
+
+	source, err := NewGoMapSource(map[string]interface{}{
+		"vus":        uint8(10),
+		"rps":        float32(0.1),
+		"iterations": 100.0,
+		"duration":   90 * time.Second,
+		"throw":      true,
+		"userAgent":  "croconf",
+		"dns": map[string]interface{}{
+			"server": net.IPv4(8, 8, 8, 8),
+			"ttl":    "5m",
+		},
+		"hosts": []string{"example.com", "k6.io"},
+		"ports": []interface{}{80, int64(443)},
+		"tags":  map[interface{}]interface{}{"foo": "bar", "baz": 1},
+		"targets": []map[string]interface{}{
+			{"url": "https://k6.io", "maxRetries": 3, "tags": []interface{}{"a", "b"}},
+			{"url": "https://example.com"},
+		},
+		"big":      uint64(1 << 63),
+		"negative": -1,
+		"empty":    nil,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var vus int16
+	var iterations uint64
+	var rps float64
+	var duration, ttl time.Duration
+	var throw bool
+	var userAgent string
+	var server net.IP
+	var hosts []string
+	var ports []uint16
+	var tags map[string]string
+	var targets []testTarget
+
+	cm := NewManager()
+	cm.AddField(NewInt16Field(&vus, source.From("vus")))
+	cm.AddField(NewUint64Field(&iterations, source.From("iterations")))
+	cm.AddField(NewFloat64Field(&rps, source.From("rps")))
+	cm.AddField(NewDurationField(&duration, source.From("duration")))
+	cm.AddField(NewDurationField(&ttl, source.From("dns").From("ttl")))
+	cm.AddField(NewBoolField(&throw, source.From("throw")))
+	cm.AddField(NewStringField(&userAgent, source.From("userAgent")))
+	cm.AddField(NewTextBasedField(&server, source.From("dns").From("server")))
+	cm.AddField(NewStringSliceField(&hosts, source.From("hosts")))
+	cm.AddField(NewUint16SliceField(&ports, source.From("ports")))
+	cm.AddField(NewStringMapField(&tags, source.From("tags")))
+	cm.AddField(NewStructSliceField(&targets, func(el *testTarget, binder ObjectValueBinder, cm *Manager) {
+		cm.AddField(NewStringField(&el.URL, binder.Property("url")))
+		cm.AddField(NewInt64Field(&el.MaxRetries, binder.Property("maxRetries")))
+		cm.AddField(NewStringSliceField(&el.Tags, binder.Property("tags")))
+	}, source.From("targets")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+
+	if vus != 10 || iterations != 100 || rps != 0.1 || duration != 90*time.Second || ttl != 5*time.Minute {
+		t.Errorf("unexpected values %d %d %g %s %s", vus, iterations, rps, duration, ttl)
+	}
+	if !throw || userAgent != "croconf" || !server.Equal(net.IPv4(8, 8, 8, 8)) {
+		t.Errorf("unexpected values %t %s %s", throw, userAgent, server)
+	}
+	if exp := []string{"example.com", "k6.io"}; !reflect.DeepEqual(hosts, exp) {
+		t.Errorf("expected hosts %v, got %v", exp, hosts)
+	}
+	if exp := []uint16{80, 443}; !reflect.DeepEqual(ports, exp) {
+		t.Errorf("expected ports %v, got %v", exp, ports)
+	}
+	if exp := map[string]string{"foo": "bar", "baz": "1"}; !reflect.DeepEqual(tags, exp) {
+		t.Errorf("expected tags %v, got %v", exp, tags)
+	}
+	expTargets := []testTarget{
+		{URL: "https://k6.io", MaxRetries: 3, Tags: []string{"a", "b"}},
+		{URL: "https://example.com"},
+	}
+	if !reflect.DeepEqual(targets, expTargets) {
+		t.Errorf("expected targets %v, got %v", expTargets, targets)
+	}
+
+	var intVal int64
+	var uintVal uint64
+	testCases := []struct {
+		binding Binding
+		expErr  string
+	}{
+		{source.From("big").BindIntValueTo(&intVal), "go map: big: value 9223372036854775808 is out of range"},
+		{source.From("negative").BindUintValueTo(&uintVal), "go map: negative: value -1 is out of range"},
+		{source.From("rps").BindIntValueTo(&intVal), "go map: rps: expected an integer, got 0.1"},
+		{source.From("userAgent").BindIntValueTo(&intVal), `go map: userAgent: expected an integer, got "croconf"`},
+		{source.From("hosts").BindIntValueTo(&intVal), "go map: hosts: expected an integer, got an array"},
+		{source.From("dns").From("missing").BindIntValueTo(&intVal), "field dns.missing is missing in config source go map"},
+		{source.From("empty").BindIntValueTo(&intVal), "field empty is missing in config source go map"},
+	}
+	for _, tc := range testCases {
+		if err := tc.binding.Apply(); err == nil || err.Error() != tc.expErr {
+			t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+		}
+	}
+
+	var smallInt int8
+	expErr := "invalid value 300, it must be between -128 and 127"
+	source, err = NewGoMapSource(map[string]interface{}{"vus": 300})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if err := NewInt8Field(&smallInt, source.From("vus")).Bindings()[0].Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
+
+func TestGoMapUnsupportedValues(t *testing.T) {
+	t.Parallel()
+
+	cyclicMap := map[string]interface{}{"a": 1}
+	cyclicMap["self"] = cyclicMap
+	cyclicSlice := []interface{}{1, nil}
+	cyclicSlice[1] = cyclicSlice
+
+	testCases := []struct {
+		values map[string]interface{}
+		expErr string
+	}{
+		{map[string]interface{}{"a": struct{}{}}, "unsupported value of type struct {} for a"},
+		{map[string]interface{}{"a": []interface{}{1, make(chan int)}}, "unsupported value of type chan int for a[1]"},
+		{map[string]interface{}{"a": map[interface{}]interface{}{1: 2}}, "unsupported map key 1 for a, only strings are supported"},
+		{map[string]interface{}{"x": map[string]interface{}{"y": cyclicMap}}, "invalid value for x.y.self, it contains a reference to itself"},
+		{map[string]interface{}{"s": cyclicSlice}, "invalid value for s[1], it contains a reference to itself"},
+	}
+
+	for _, tc := range testCases {
+		if _, err := NewGoMapSource(tc.values); err == nil || err.Error() != tc.expErr {
+			t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+		}
+	}
+
+	// the same value can be used more than once, as long as it doesn't contain itself
+	shared := map[string]interface{}{"a": 1}
+	if _, err := NewGoMapSource(map[string]interface{}{"a": shared, "b": []interface{}{shared, shared}}); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+}
+
+func TestGoMapUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	source, err := NewGoMapSource(map[string]interface{}{
+		"vus": 10,
+		"dns": map[string]interface{}{"server": "8.8.8.8", "tll": 5},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	// the same source can be used by multiple managers, e.g. for sub-commands,
+	// so the used values are tracked separately for every consolidation
+	var vus, ttl int64
+	var server string
+	cm := NewManager()
+	cm.AddField(NewInt64Field(&vus, source.From("vus")))
+	cm.AddField(NewInt64Field(&ttl, source.From("dns").From("ttl")))
+	cm.AddField(NewStringField(&server, source.From("dns").From("server")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+	expWarnings := []string{"go map: unknown key dns.tll, did you mean dns.ttl?"}
+	if warnings := fmt.Sprint(cm.Warnings()); warnings != fmt.Sprint(expWarnings) {
+		t.Errorf("expected warnings %q, got %q", expWarnings, warnings)
+	}
+
+	cm = NewManager(WithStrictUnknownValues())
+	cm.AddField(NewStringField(&server, source.From("dns").From("server")))
+	expErr := "Unknown config values: \n\t- go map: unknown key dns.tll\n\t- go map: unknown key vus"
+	if err := cm.Consolidate(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
@@ -0,0 +1,319 @@
+package croconf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SourceINI is a config source for INI files with [section] headers and for
+// Java-style .properties files. Sections and dotted keys are nested, so the
+// value of `key` in `[section]` is available with From("section").From("key")
+// and its bound name is "section.key". Keys can also be the prefixes of other
+// keys, e.g. a.b=1 and a.b.c=2. All values are strings and repeated keys or
+// comma-separated lists can be bound to slices, while the other binders use
+// the last value of repeated keys.
+type SourceINI struct {
+	doc  *treeDocument
+	name string
+	init func() error
+}
+
+type INISourceOption func(*SourceINI)
+
+// WithINIFileName sets the file name that is used in the parse and value
+// error messages, instead of the generic "ini" or "properties" source names.
+func WithINIFileName(fileName string) INISourceOption {
+	return func(si *SourceINI) {
+		si.doc.fileName = fileName
+	}
+}
+
+// NewINISource creates a source for INI files. Comments start with ; or #,
+// keys and values can be separated by = or :, and values can be quoted.
+func NewINISource(data []byte, options ...INISourceOption) *SourceINI {
+	return newINISource("ini", parseINI, data, options)
+}
+
+// NewPropertiesSource creates a source for Java-style .properties files, see
+// https://docs.oracle.com/javase/8/docs/api/java/util/Properties.html#load-java.io.Reader-
+func NewPropertiesSource(data []byte, options ...INISourceOption) *SourceINI {
+	return newINISource("properties", parseProperties, data, options)
+}
+
+func newINISource(
+	name string, parse func(string) (*treeNode, error), data []byte, options []INISourceOption,
+) *SourceINI {
+	si := &SourceINI{name: name}
+	si.doc = &treeDocument{source: si, splitLists: true}
+	for _, opt := range options {
+		opt(si)
+	}
+
+	si.init = func() error {
+		return si.doc.init(func() (*treeNode, error) {
+			return parse(string(data))
+		})
+	}
+	return si
+}
+
+func (si *SourceINI) Initialize() error {
+	return si.init()
+}
+
+func (si *SourceINI) GetName() string {
+	return si.name
+}
+
+func (si *SourceINI) From(name string) *treeBinder {
+	return si.doc.from(name)
+}
+
+func (si *SourceINI) FromPath(path ...string) DocumentValueBinder {
+	return si.doc.fromPath(path)
+}
+
+// UnknownValues returns errors for all keys in the document that weren't used
+// by any of the fields.
+func (si *SourceINI) UnknownValues() []error {
+	return si.doc.UnknownValues()
+}
+
+// iniTree builds the tree of nested sections and keys.
+type iniTree struct {
+	root *treeNode
+}
+
+func (it *iniTree) errorf(line, column int, format string, args ...interface{}) error {
+	return &treeSyntaxError{line: line, column: column, msg: fmt.Sprintf(format, args...)}
+}
+
+func splitINIKey(key string) ([]string, bool) {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+		if parts[i] == "" {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+// object returns the (nested) object with the given path, creating it if it
+// doesn't exist. Any existing values with the same path are kept as the scalar
+// values of the objects.
+func (it *iniTree) object(path []string, line, column int) *treeNode {
+	node := it.root
+	for _, part := range path {
+		next, exists := node.props[part]
+		if !exists {
+			next = newTreeObject(line, column)
+			node.set(part, next)
+		} else if next.kind != treeObject {
+			obj := newTreeObject(next.line, next.column)
+			obj.scalar = next
+			node.set(part, obj)
+			next = obj
+		}
+		node = next
+	}
+	return node
+}
+
+// set adds the value for the given key.
+func (it *iniTree) set(path []string, value string, line, column int) {
+	parent := it.object(path[:len(path)-1], line, column)
+	key := path[len(path)-1]
+	parent.set(key, appendINIValue(parent.props[key], &treeNode{
+		kind: treeScalar, value: value, raw: value, line: line, column: column,
+	}))
+}
+
+// appendINIValue adds the value to the existing node of its key, if any.
+// Repeated keys become arrays, with their last value as the scalar one.
+func appendINIValue(existing, value *treeNode) *treeNode {
+	switch {
+	case existing == nil:
+		return value
+	case existing.kind == treeScalar:
+		return &treeNode{
+			kind: treeArray, elements: []*treeNode{existing, value}, scalar: value,
+			line: existing.line, column: existing.column,
+		}
+	case existing.kind == treeArray:
+		existing.elements = append(existing.elements, value)
+		existing.scalar = value
+	default:
+		existing.scalar = appendINIValue(existing.scalar, value)
+	}
+	return existing
+}
+
+func parseINI(data string) (*treeNode, error) {
+	it := &iniTree{root: newTreeObject(1, 1)}
+	var section []string
+	for i, line := range strings.Split(data, "\n") {
+		lineNum := i + 1
+		content := strings.TrimSpace(line)
+		if content == "" || content[0] == ';' || content[0] == '#' {
+			continue
+		}
+		column := strings.Index(line, content) + 1
+
+		if content[0] == '[' {
+			end := strings.IndexByte(content, ']')
+			if end < 0 {
+				return nil, it.errorf(lineNum, column, "unterminated section header")
+			}
+			if rest := strings.TrimSpace(content[end+1:]); rest != "" && rest[0] != ';' && rest[0] != '#' {
+				return nil, it.errorf(lineNum, column+end+1, "unexpected content after the section header")
+			}
+			var ok bool
+			if section, ok = splitINIKey(content[1:end]); !ok {
+				return nil, it.errorf(lineNum, column+1, "invalid section name %q", content[1:end])
+			}
+			it.object(section, lineNum, column)
+			continue
+		}
+
+		sep := strings.IndexAny(content, "=:")
+		if sep < 0 {
+			return nil, it.errorf(lineNum, column, "expected a 'key = value' pair")
+		}
+		key, ok := splitINIKey(content[:sep])
+		if !ok {
+			return nil, it.errorf(lineNum, column, "invalid key %q", strings.TrimSpace(content[:sep]))
+		}
+
+		rawValue := content[sep+1:]
+		valueColumn := column + sep + 1 + len(rawValue) - len(strings.TrimLeft(rawValue, " \t"))
+		value := unquoteINIValue(strings.TrimSpace(stripINIComment(rawValue)))
+
+		path := append(append([]string{}, section...), key...)
+		it.set(path, value, lineNum, valueColumn)
+	}
+	return it.root, nil
+}
+
+// stripINIComment removes inline comments, i.e. ; or # preceded by whitespace.
+// They are not recognized inside of a quoted value, e.g. "a ; b".
+func stripINIComment(value string) string {
+	start := 1
+	if trimmed := strings.TrimLeft(value, " \t"); trimmed != "" && (trimmed[0] == '"' || trimmed[0] == '\'') {
+		quoteStart := len(value) - len(trimmed)
+		if quoteEnd := strings.IndexByte(value[quoteStart+1:], trimmed[0]); quoteEnd >= 0 {
+			start = quoteStart + quoteEnd + 2
+		}
+	}
+	for i := start; i < len(value); i++ {
+		if (value[i] == ';' || value[i] == '#') && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return value[:i]
+		}
+	}
+	return value
+}
+
+func unquoteINIValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func parseProperties(data string) (*treeNode, error) {
+	it := &iniTree{root: newTreeObject(1, 1)}
+	lines := strings.Split(data, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		content := strings.TrimLeft(strings.TrimSuffix(lines[i], "\r"), " \t\f")
+		if content == "" || content[0] == '#' || content[0] == '!' {
+			continue
+		}
+		column := len(strings.TrimSuffix(lines[i], "\r")) - len(content) + 1
+
+		// Lines that end with an odd number of backslashes are continued on
+		// the next line, without its leading whitespace.
+		for strings.HasSuffix(content, `\`) && (len(content)-len(strings.TrimRight(content, `\`)))%2 == 1 {
+			content = content[:len(content)-1]
+			if i+1 < len(lines) {
+				i++
+				content += strings.TrimLeft(strings.TrimSuffix(lines[i], "\r"), " \t\f")
+			}
+		}
+
+		key, valueStart, err := unescapeProperty(content, true)
+		if err != nil {
+			return nil, it.errorf(lineNum, column, "%s", err)
+		}
+		value, _, err := unescapeProperty(content[valueStart:], false)
+		if err != nil {
+			return nil, it.errorf(lineNum, column+valueStart, "%s", err)
+		}
+
+		path, ok := splitINIKey(key)
+		if !ok {
+			return nil, it.errorf(lineNum, column, "invalid key %q", key)
+		}
+		it.set(path, value, lineNum, column+valueStart)
+	}
+	return it.root, nil
+}
+
+// unescapeProperty decodes the escape sequences in the key or value of a
+// .properties line. For keys, it stops at the first unescaped separator and
+// returns the offset of the value.
+func unescapeProperty(s string, isKey bool) (string, int, error) {
+	var sb strings.Builder
+	i := 0
+	for ; i < len(s); i++ {
+		c := s[i]
+		if isKey && strings.IndexByte("=: \t\f", c) >= 0 {
+			break
+		}
+		if c != '\\' || i+1 >= len(s) {
+			sb.WriteByte(c)
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			sb.WriteByte('\t')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", 0, fmt.Errorf("invalid escape sequence '\\%s'", s[i:])
+			}
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(code)) {
+				return "", 0, fmt.Errorf("invalid escape sequence '\\%s'", s[i:i+5])
+			}
+			sb.WriteRune(rune(code))
+			i += 4
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	if !isKey {
+		return sb.String(), i, nil
+	}
+
+	// skip the whitespace around the separator, and at most one '=' or ':'
+	for i < len(s) && strings.IndexByte(" \t\f", s[i]) >= 0 {
+		i++
+	}
+	if i < len(s) && (s[i] == '=' || s[i] == ':') {
+		i++
+	}
+	for i < len(s) && strings.IndexByte(" \t\f", s[i]) >= 0 {
+		i++
+	}
+	return sb.String(), i, nil
+}
@@ -0,0 +1,226 @@
+package croconf
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testINIConfig = `
+; comments should be ignored
+vus = 10
+duration: 1m30s
+
+[dns]
+server = "8.8.8.8" ; an inline comment
+search = "a ; b # c" # the comment chars in the quotes are a part of the value
+ttl = 5m
+
+[dns.cache]
+size = 100
+
+[hosts]
+list = example.com, k6.io
+host = example.com
+host = k6.io
+
+[tags]
+foo = bar
+baz = 1
+`
+
+func TestINIBindValues(t *testing.T) {
+	t.Parallel()
+
+	source := NewINISource([]byte(testINIConfig))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var vus, cacheSize int64
+	var duration, ttl time.Duration
+	var server, search string
+	var list, hosts []string
+	var tags map[string]string
+
+	cm := NewManager()
+	cm.AddField(NewInt64Field(&vus, source.From("vus")))
+	cm.AddField(NewDurationField(&duration, source.From("duration")))
+	cm.AddField(NewStringField(&server, source.From("dns").From("server")))
+	cm.AddField(NewStringField(&search, source.From("dns").From("search")))
+	cm.AddField(NewDurationField(&ttl, source.From("dns").From("ttl")))
+	cm.AddField(NewInt64Field(&cacheSize, source.From("dns").From("cache").From("size")))
+	cm.AddField(NewStringSliceField(&list, source.From("hosts").From("list")))
+	cm.AddField(NewStringSliceField(&hosts, source.From("hosts").From("host")))
+	cm.AddField(NewStringMapField(&tags, source.From("tags")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+
+	if vus != 10 || duration != 90*time.Second || ttl != 5*time.Minute || cacheSize != 100 || server != "8.8.8.8" {
+		t.Errorf("unexpected values %d %s %s %d %s", vus, duration, ttl, cacheSize, server)
+	}
+	if search != "a ; b # c" {
+		t.Errorf("expected a quoted value with comment chars, got %q", search)
+	}
+	exp := []string{"example.com", "k6.io"}
+	if !reflect.DeepEqual(list, exp) || !reflect.DeepEqual(hosts, exp) {
+		t.Errorf("expected %v, got %v and %v", exp, list, hosts)
+	}
+	if exp := map[string]string{"foo": "bar", "baz": "1"}; !reflect.DeepEqual(tags, exp) {
+		t.Errorf("expected tags %v, got %v", exp, tags)
+	}
+	if name := cm.Field(&cacheSize).Name; name != "dns.cache.size" {
+		t.Errorf("expected field name dns.cache.size, got %s", name)
+	}
+
+	expErr := `ini:9:7: dns.ttl: expected an integer, got "5m"`
+	if err := source.From("dns").From("ttl").BindIntValueTo(&vus).Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+	expErr = "field dns.missing is missing in config source ini"
+	if err := source.From("dns").From("missing").BindIntValueTo(&vus).Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
+
+func TestPropertiesBindValues(t *testing.T) {
+	t.Parallel()
+
+	properties := `
+# comments should be ignored
+! this is a comment too
+server.port=8080
+server.host : localhost
+app.name   My \
+           App
+app.path = C:\\app\u00e9
+tags.foo bar
+`
+	source := NewPropertiesSource([]byte(properties), WithINIFileName("app.properties"))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var port uint64
+	var host, name, path string
+	var tags map[string]string
+	bindings := []Binding{
+		source.From("server").From("port").BindUintValueTo(&port),
+		source.From("server").From("host").BindStringValueTo(&host),
+		source.From("app").From("name").BindStringValueTo(&name),
+		source.From("app").From("path").BindStringValueTo(&path),
+	}
+	for _, b := range bindings {
+		if err := b.Apply(); err != nil {
+			t.Fatalf("unexpected error for %s: %s", b.(BindingFromSource).BoundName(), err)
+		}
+	}
+	if err := NewStringMapField(&tags, source.From("tags")).Bindings()[0].Apply(); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	if port != 8080 || host != "localhost" || name != "My App" || path != `C:\appé` {
+		t.Errorf("unexpected values %d %q %q %q", port, host, name, path)
+	}
+	if exp := map[string]string{"foo": "bar"}; !reflect.DeepEqual(tags, exp) {
+		t.Errorf("expected tags %v, got %v", exp, tags)
+	}
+
+	binding := source.From("server").From("host").BindIntValueTo(new(int64))
+	if bfs := binding.(BindingFromSource); bfs.BoundName() != "server.host" || bfs.Source() != source {
+		t.Errorf("unexpected bound name %s or source", bfs.BoundName())
+	}
+	expErr := `app.properties:5:15: server.host: expected an integer, got "localhost"`
+	if err := binding.Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
+
+func TestINIParseErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		ini    string
+		expErr string
+	}{
+		{"[section", "ini:1:1: unterminated section header"},
+		{"[a] b", "ini:1:4: unexpected content after the section header"},
+		{"[a..b]", `ini:1:2: invalid section name "a..b"`},
+		{"a = 1\nb", "ini:2:1: expected a 'key = value' pair"},
+		{" = 1", `ini:1:2: invalid key ""`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.ini, func(t *testing.T) {
+			t.Parallel()
+			err := NewINISource([]byte(tc.ini)).Initialize()
+			if err == nil || err.Error() != tc.expErr {
+				t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestPropertiesPrefixAndRepeatedKeys(t *testing.T) {
+	t.Parallel()
+
+	properties := `
+log4j.appender.stdout=org.apache.log4j.ConsoleAppender
+log4j.appender.stdout.layout=org.apache.log4j.PatternLayout
+log4j.appender.stdout.layout.pattern=%m%n
+host=example.com
+host=k6.io
+log4j.appender.stdout=org.apache.log4j.FileAppender
+`
+	source := NewPropertiesSource([]byte(properties))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var appender, layout, pattern, host string
+	var appenders, hosts []string
+	cm := NewManager()
+	stdout := source.From("log4j").From("appender").From("stdout")
+	cm.AddField(NewStringField(&appender, stdout))
+	cm.AddField(NewStringField(&layout, stdout.From("layout")))
+	cm.AddField(NewStringField(&pattern, stdout.From("layout").From("pattern")))
+	cm.AddField(NewStringField(&host, source.From("host")))
+	cm.AddField(NewStringSliceField(&appenders, stdout))
+	cm.AddField(NewStringSliceField(&hosts, source.From("host")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+
+	if appender != "org.apache.log4j.FileAppender" || layout != "org.apache.log4j.PatternLayout" || pattern != "%m%n" {
+		t.Errorf("unexpected values %q %q %q", appender, layout, pattern)
+	}
+	if host != "k6.io" {
+		t.Errorf("expected the last value of the repeated key, got %q", host)
+	}
+	if exp := []string{"example.com", "k6.io"}; !reflect.DeepEqual(hosts, exp) {
+		t.Errorf("expected hosts %v, got %v", exp, hosts)
+	}
+	if exp := []string{"org.apache.log4j.ConsoleAppender", "org.apache.log4j.FileAppender"}; !reflect.DeepEqual(appenders, exp) {
+		t.Errorf("expected appenders %v, got %v", exp, appenders)
+	}
+
+	var port int64
+	expErr := `properties:6:6: host: expected an integer, got "k6.io"`
+	if err := source.From("host").BindIntValueTo(&port).Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	source = NewINISource([]byte("a = 1\n[a]\nb = 2"))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+	var a, b int64
+	if err := source.From("a").BindIntValueTo(&a).Apply(); err != nil || a != 1 {
+		t.Errorf("unexpected value %d or error %s", a, err)
+	}
+	if err := source.From("a").From("b").BindIntValueTo(&b).Apply(); err != nil || b != 2 {
+		t.Errorf("unexpected value %d or error %s", b, err)
+	}
+}
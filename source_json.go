@@ -1,35 +1,141 @@
 package croconf
 
 import (
+	"bytes"
 	"encoding"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
 )
 
-// TODO: use json.Decoder for this? json.Unmarshal() is a bit too magical
-
 // TODO: rename this to something else? e.g. JSONDocument?
 type SourceJSON struct {
-	fields map[string]json.RawMessage
-	init   func() error
-}
-
-func NewJSONSource(data []byte) *SourceJSON {
-	fields := make(map[string]json.RawMessage)
-	return &SourceJSON{
-		fields: fields,
-		init: func() error {
-			// TODO: differentiate between an empty data and no data (nil)?
-			if len(data) == 0 {
-				return nil
-			}
-			err := json.Unmarshal(data, &fields)
+	root     *jsonValue
+	init     func() error
+	relaxed  bool
+	fileName string
+
+	// data is the original document and originalOffset maps the offsets of the
+	// values to offsets in it, in case the document had to be converted first
+	data           []byte
+	originalOffset func(int) int
+}
+
+// jsonValue is a raw JSON value, together with its byte offset in the parsed
+// document, so its position can be used in error messages. The whole document
+// is indexed when the source is initialized, so objects and arrays also have
+// their properties and elements, and they don't need to be parsed again when
+// their nested values are looked up.
+type jsonValue struct {
+	raw    json.RawMessage
+	offset int
+
+	keys     []string // for objects, in the original order
+	props    map[string]*jsonValue
+	elements []*jsonValue // for arrays
+
+	// used is set when the value is successfully bound, or when it's an
+	// object that nested values were looked up in, and requested has all
+	// property names that were looked up in an object, even missing ones, so
+	// they can be suggested as alternatives to any unknown keys
+	used      bool
+	requested map[string]bool
+}
+
+func (jv *jsonValue) isObject() bool {
+	return jv.props != nil
+}
+
+func (jv *jsonValue) isArray() bool {
+	return jv.elements != nil
+}
+
+func (jv *jsonValue) isNull() bool {
+	return string(jv.raw) == "null"
+}
+
+// property returns the given property of the object and records that it was
+// requested. The property itself is only marked as used once it's bound.
+func (jv *jsonValue) property(name string) (*jsonValue, bool) {
+	if jv.requested == nil {
+		jv.requested = make(map[string]bool)
+	}
+	jv.requested[name] = true
+	prop, ok := jv.props[name]
+	return prop, ok
+}
+
+// markAllUsed marks the value and all of its nested values as used, for when
+// the whole raw value is consumed at once, e.g. by json.Unmarshaler types.
+func (jv *jsonValue) markAllUsed() {
+	jv.used = true
+	for _, prop := range jv.props {
+		prop.markAllUsed()
+	}
+	for _, el := range jv.elements {
+		el.markAllUsed()
+	}
+}
+
+type JSONSourceOption func(*SourceJSON)
+
+// WithRelaxedJSON allows the JSON document to have // and /* */ comments,
+// trailing commas, unquoted object keys and 'single-quoted' strings, similar
+// to JSONC and JSON5. The errors still point to the positions in the original
+// document.
+func WithRelaxedJSON() JSONSourceOption {
+	return func(sj *SourceJSON) {
+		sj.relaxed = true
+	}
+}
+
+// WithJSONFileName sets the file name that is used in the parse and value
+// error messages, instead of the generic "json" source name.
+func WithJSONFileName(fileName string) JSONSourceOption {
+	return func(sj *SourceJSON) {
+		sj.fileName = fileName
+	}
+}
+
+func NewJSONSource(data []byte, options ...JSONSourceOption) *SourceJSON {
+	sj := &SourceJSON{
+		root:           &jsonValue{raw: json.RawMessage("{}"), props: make(map[string]*jsonValue)},
+		data:           data,
+		originalOffset: func(offset int) int { return offset },
+	}
+	for _, opt := range options {
+		opt(sj)
+	}
+
+	sj.init = func() error {
+		// TODO: differentiate between an empty data and no data (nil)?
+		if len(data) == 0 {
+			return nil
+		}
+		strictData := data
+		if sj.relaxed {
+			converted, originalOffset, err := convertRelaxedJSON(data)
 			if err != nil {
-				return NewJSONSourceInitError(data, err)
+				return sj.initError(err)
 			}
-			return nil
-		},
+			strictData, sj.originalOffset = converted, originalOffset
+		}
+
+		root, err := indexJSON(strictData)
+		if err != nil {
+			if sj.relaxed {
+				err = relaxedJSONError(err, strictData, data, sj.originalOffset)
+			}
+			return sj.initError(err)
+		}
+		sj.root = root
+		return nil
 	}
+	return sj
 }
 
 func (sj *SourceJSON) Initialize() error {
@@ -41,8 +147,231 @@ func (sj *SourceJSON) GetName() string {
 }
 
 func (sj *SourceJSON) Lookup(name string) (json.RawMessage, bool) {
-	res, ok := sj.fields[name]
-	return res, ok
+	res, ok := sj.root.property(name)
+	if !ok {
+		return nil, false
+	}
+	res.markAllUsed()
+	return res.raw, true
+}
+
+// UnknownValues returns errors for all keys in the document, including nested
+// ones, that weren't looked up by any binder. They can only be detected after
+// all fields have been consolidated.
+func (sj *SourceJSON) UnknownValues() []error {
+	var errs []error
+	sj.root.used = true
+	sj.collectUnknownKeys(sj.root, "", &errs)
+	return errs
+}
+
+func (sj *SourceJSON) collectUnknownKeys(value *jsonValue, path string, errs *[]error) {
+	if !value.used {
+		return
+	}
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	for _, key := range value.keys {
+		prop := value.props[key]
+		if prop.used {
+			sj.collectUnknownKeys(prop, prefix+key, errs)
+			continue
+		}
+		known := make([]string, 0, len(value.requested))
+		for name := range value.requested {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		suggestion := closestName(key, known)
+		if suggestion != "" {
+			suggestion = prefix + suggestion
+		}
+		*errs = append(*errs, NewUnknownValueError(sj.location(prop), "key", prefix+key, suggestion))
+	}
+	for i, el := range value.elements {
+		sj.collectUnknownKeys(el, fmt.Sprintf("%s[%d]", path, i), errs)
+	}
+}
+
+func (sj *SourceJSON) name() string {
+	if sj.fileName != "" {
+		return sj.fileName
+	}
+	return sj.GetName()
+}
+
+func (sj *SourceJSON) initError(err error) error {
+	return newJSONInitError(sj.data, sj.name(), err, sj.originalOffset)
+}
+
+// newJSONInitError returns a JSONSourceInitError for the given error from
+// indexJSON(). If the document was converted before it was indexed,
+// originalOffset maps the offsets in the converted document to the original.
+func newJSONInitError(data []byte, location string, err error, originalOffset func(int) int) error {
+	initErr := &JSONSourceInitError{Data: data, Location: location, Err: err}
+	if offset := jsonErrorOffset(err); offset >= 0 {
+		if originalOffset != nil {
+			offset = originalOffset(offset)
+		}
+		initErr.Line, initErr.Column = textPosition(data, offset)
+	}
+	return initErr
+}
+
+func (sj *SourceJSON) location(value *jsonValue) string {
+	line, column := textPosition(sj.data, sj.originalOffset(value.offset))
+	return formatLocation(sj.name(), line, column)
+}
+
+// jsonOffsetError is an error at a specific byte offset of a JSON document.
+type jsonOffsetError struct {
+	offset int
+	err    error
+}
+
+func (e *jsonOffsetError) Error() string { return e.err.Error() }
+
+func (e *jsonOffsetError) Unwrap() error { return e.err }
+
+// jsonErrorOffset returns the offset of the problem that caused the error, or
+// -1 if it's unknown.
+func jsonErrorOffset(err error) int {
+	var offsetErr *jsonOffsetError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &offsetErr):
+		return offsetErr.offset
+	case errors.As(err, &syntaxErr) && syntaxErr.Offset > 0:
+		return int(syntaxErr.Offset) - 1
+	}
+	return -1
+}
+
+// indexJSON parses the JSON document in a single pass and indexes all of its
+// values, which have to be in a top-level object.
+func indexJSON(data []byte) (*jsonValue, error) {
+	ix := &jsonIndexer{dec: json.NewDecoder(bytes.NewReader(data)), data: data}
+	ix.dec.UseNumber() // we only need to validate the numbers, not parse them
+
+	var root *jsonValue
+	if first := skipJSONSeparators(data, 0); first < len(data) && data[first] != '{' {
+		token, err := ix.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if token != nil {
+			return nil, &jsonOffsetError{
+				offset: first,
+				err:    fmt.Errorf("expected an object, got %s", describeJSONToken(token)),
+			}
+		}
+		// Like with json.Unmarshal() into a map, null documents are empty
+		root = &jsonValue{raw: json.RawMessage("{}"), offset: first, props: make(map[string]*jsonValue)}
+	} else {
+		var err error
+		if root, err = ix.value(); err != nil {
+			return nil, err
+		}
+	}
+
+	end := int(ix.dec.InputOffset())
+	if _, err := ix.dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, &jsonOffsetError{
+			offset: skipJSONSeparators(data, end),
+			err:    errors.New("unexpected content after the end of the document"),
+		}
+	}
+	return root, nil
+}
+
+type jsonIndexer struct {
+	dec  *json.Decoder
+	data []byte
+}
+
+func skipJSONSeparators(raw []byte, offset int) int {
+	for offset < len(raw) && bytes.IndexByte([]byte(" \t\r\n:,"), raw[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// value indexes the next value in the document, with all of its properties or
+// elements if it's an object or an array. Its offset is found by skipping the
+// whitespace and the separators after the previous token.
+func (ix *jsonIndexer) value() (*jsonValue, error) {
+	start := skipJSONSeparators(ix.data, int(ix.dec.InputOffset()))
+	token, err := ix.dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	val := &jsonValue{offset: start}
+	switch token {
+	case json.Delim('{'):
+		val.props = make(map[string]*jsonValue)
+		for ix.dec.More() {
+			keyToken, err := ix.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyToken.(string) // the decoder ensures that object keys are strings
+			prop, err := ix.value()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := val.props[key]; !ok {
+				val.keys = append(val.keys, key)
+			}
+			val.props[key] = prop // like json.Unmarshal(), the last duplicate key wins
+		}
+	case json.Delim('['):
+		val.elements = make([]*jsonValue, 0)
+		for ix.dec.More() {
+			el, err := ix.value()
+			if err != nil {
+				return nil, err
+			}
+			val.elements = append(val.elements, el)
+		}
+	}
+	if val.isObject() || val.isArray() {
+		if _, err := ix.dec.Token(); err != nil { // the closing } or ]
+			return nil, err
+		}
+	}
+
+	val.raw = ix.data[start:ix.dec.InputOffset()]
+	return val, nil
+}
+
+func describeJSONToken(token json.Token) string {
+	switch val := token.(type) {
+	case json.Delim:
+		return describeJSON(string(val))
+	case string:
+		return strconv.Quote(val)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprint(token)
+}
+
+// describeJSON returns a short human-readable description of the raw JSON
+// value, to be used in error messages.
+func describeJSON(raw string) string {
+	switch {
+	case raw == "":
+		return "nothing"
+	case raw[0] == '{':
+		return "an object"
+	case raw[0] == '[':
+		return "an array"
+	}
+	return raw
 }
 
 func (jb *jsonBinder) newBinding(apply func() error) *jsonBinding {
@@ -56,125 +385,162 @@ func (sj *SourceJSON) From(name string) *jsonBinder {
 	return &jsonBinder{
 		source: sj,
 		name:   name,
-		lookup: func() (json.RawMessage, error) {
-			raw, ok := sj.Lookup(name)
+		lookup: func() (*jsonValue, error) {
+			value, ok := sj.root.property(name)
 			if !ok {
 				return nil, NewBindFieldMissingError(sj.GetName(), name)
 			}
-			return raw, nil
+			return value, nil
 		},
 	}
 }
 
+// FromPath returns the binder for a nested value, e.g. FromPath("dns", "ttl")
+// is the same as From("dns").From("ttl"). The path can't be empty.
+func (sj *SourceJSON) FromPath(path ...string) DocumentValueBinder {
+	binder := sj.From(path[0])
+	for _, part := range path[1:] {
+		binder = binder.From(part)
+	}
+	return binder
+}
+
 // TODO: export and rename? e.g. to JSONProperty?
 type jsonBinder struct {
-	source Source
-	lookup func() (json.RawMessage, error)
+	source *SourceJSON
+	lookup func() (*jsonValue, error)
 	name   string
 }
 
 func (jb *jsonBinder) From(name string) *jsonBinder {
+	fullName := jb.name + "." + name
 	return &jsonBinder{
 		source: jb.source,
-		name:   jb.name + "." + name,
-		lookup: func() (json.RawMessage, error) {
-			raw, err := jb.lookup()
+		name:   fullName,
+		lookup: func() (*jsonValue, error) {
+			value, err := jb.lookup()
 			if err != nil {
 				return nil, err
 			}
-
-			// TODO: cache this, so we don't parse sub-configs multiple times
-			subdoc := NewJSONSource(raw)
-			if err := subdoc.init(); err != nil {
-				return nil, NewJSONSourceInitError(raw, err)
+			if value.isNull() {
+				// Like a missing parent, so the defaults of the nested values
+				// are used for empty placeholders like {"dns": null}
+				return nil, NewBindFieldMissingError(jb.source.GetName(), fullName)
+			}
+			if !value.isObject() {
+				return nil, jb.valueError(value, "expected an object, got %s", describeJSON(string(value.raw)))
 			}
 
-			rawEl, ok := subdoc.Lookup(name)
+			value.used = true
+			el, ok := value.property(name)
 			if !ok {
-				return nil, NewBindFieldMissingError(subdoc.GetName(), name)
+				return nil, NewBindFieldMissingError(jb.source.GetName(), fullName)
 			}
-			return rawEl, nil
+			return el, nil
 		},
 	}
 }
 
-func (jb *jsonBinder) BindStringValueTo(dest *string) Binding {
-	return jb.newBinding(func() error {
-		raw, err := jb.lookup()
+func (jb *jsonBinder) valueError(value *jsonValue, format string, args ...interface{}) error {
+	return NewSourceValueError(jb.source.location(value), jb.name, fmt.Errorf(format, args...))
+}
+
+// bindValue looks up the value and calls bind with it. BindValueError and
+// json.UnmarshalTypeError errors are converted to simpler error messages, and
+// all errors are annotated with the location of the value.
+func (jb *jsonBinder) bindValue(expected string, bind func(raw json.RawMessage) error) func() error {
+	return func() error {
+		value, err := jb.lookup()
 		if err != nil {
 			return err
 		}
+		if err := bind(value.raw); err != nil {
+			var bindErr *BindValueError
+			var typeErr *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &bindErr) && errors.Is(bindErr.Err, strconv.ErrRange):
+				err = fmt.Errorf("value %s is out of range", describeJSON(string(value.raw)))
+			case errors.As(err, &bindErr), errors.As(err, &typeErr):
+				err = fmt.Errorf("expected %s, got %s", expected, describeJSON(string(value.raw)))
+			}
+			return NewSourceValueError(jb.source.location(value), jb.name, err)
+		}
+		value.markAllUsed() // json.Unmarshaler types can consume whole objects
+		return nil
+	}
+}
 
-		return json.Unmarshal(raw, dest) // TODO: less reflection, better error messages
-	})
+func (jb *jsonBinder) BindStringValueTo(dest *string) Binding {
+	return jb.newBinding(jb.bindValue("a string", func(raw json.RawMessage) error {
+		return json.Unmarshal(raw, dest) // TODO: less reflection
+	}))
 }
 
 func (jb *jsonBinder) BindIntValueTo(dest *int64) Binding {
-	return jb.newBinding(func() error {
-		raw, err := jb.lookup()
-		if err != nil {
-			// TODO: we might want to integrate custom error into lookup() method
-			return NewBindFieldMissingError(jb.source.GetName(), jb.name)
-		}
+	return jb.newBinding(jb.bindValue("an integer", func(raw json.RawMessage) error {
 		intVal, bindErr := parseInt(string(raw))
 		if bindErr != nil {
-			return bindErr.withFuncName("BindIntValue")
+			return bindErr
 		}
 		*dest = intVal
 		return nil
-	})
+	}))
 }
 
 func (jb *jsonBinder) BindUintValueTo(dest *uint64) Binding {
-	return jb.newBinding(func() error {
-		raw, err := jb.lookup()
-		if err != nil {
-			// TODO: we might want to integrate custom error into lookup() method
-			return NewBindFieldMissingError(jb.source.GetName(), jb.name)
-		}
+	return jb.newBinding(jb.bindValue("an unsigned integer", func(raw json.RawMessage) error {
 		uintVal, bindErr := parseUint(string(raw))
 		if bindErr != nil {
-			return bindErr.withFuncName("BindIntValue")
+			return bindErr
 		}
 		*dest = uintVal
 		return nil
-	})
+	}))
 }
 
 func (jb *jsonBinder) BindFloatValueTo(dest *float64) Binding {
-	return jb.newBinding(func() error {
-		raw, err := jb.lookup()
-		if err != nil {
-			// TODO: we might want to integrate custom error into lookup() method
-			return NewBindFieldMissingError(jb.source.GetName(), jb.name)
-		}
+	return jb.newBinding(jb.bindValue("a number", func(raw json.RawMessage) error {
 		floatVal, bindErr := parseFloat(string(raw))
 		if bindErr != nil {
-			return bindErr.withFuncName("BindIntValue")
+			return bindErr
 		}
 		*dest = floatVal
 		return nil
-	})
+	}))
 }
 
-func (jb *jsonBinder) BindBoolValueTo(dest *bool) Binding {
-	return jb.newBinding(func() error {
-		raw, err := jb.lookup()
-		if err != nil {
-			return err
+// BindDurationValueTo accepts both strings, e.g. "1m30s" or "2d", and numbers,
+// which are multiplied by bareNumberUnit.
+func (jb *jsonBinder) BindDurationValueTo(dest *time.Duration, bareNumberUnit time.Duration) Binding {
+	return jb.newBinding(jb.bindValue("a string or a number", func(raw json.RawMessage) error {
+		strVal := string(raw)
+		if len(raw) > 0 && raw[0] == '"' {
+			if err := json.Unmarshal(raw, &strVal); err != nil {
+				return err
+			}
+		} else if _, bindErr := parseFloat(strVal); bindErr != nil {
+			return bindErr
 		}
 
-		return json.Unmarshal(raw, dest) // TODO: less reflection, better error messages
-	})
+		val, bindErr := parseDuration(strVal, bareNumberUnit)
+		if bindErr != nil {
+			// The specific reason, e.g. "invalid duration after the days", is
+			// more useful than a generic "expected a duration" message
+			return fmt.Errorf("%s: %w", describeJSON(string(raw)), bindErr.Err)
+		}
+		*dest = val
+		return nil
+	}))
 }
 
-func (jb *jsonBinder) BindTextBasedValueTo(dest encoding.TextUnmarshaler) Binding {
-	return jb.newBinding(func() error {
-		raw, err := jb.lookup()
-		if err != nil {
-			return err
-		}
+func (jb *jsonBinder) BindBoolValueTo(dest *bool) Binding {
+	return jb.newBinding(jb.bindValue("a boolean", func(raw json.RawMessage) error {
+		return json.Unmarshal(raw, dest) // TODO: less reflection
+	}))
+}
 
+func (jb *jsonBinder) BindTextBasedValueTo(dest encoding.TextUnmarshaler) Binding {
+	return jb.newBinding(jb.bindValue("a string", func(raw json.RawMessage) error {
 		// Progressive enhancement ¯\_(ツ)_/¯ If the destination supports directly
 		// unmarshaling JSON, we should use that. Otherwise, we will fall back to
 		// the simple text unmarshaling we know we can rely on.
@@ -182,13 +548,15 @@ func (jb *jsonBinder) BindTextBasedValueTo(dest encoding.TextUnmarshaler) Bindin
 			return jum.UnmarshalJSON(raw)
 		}
 
-		rawLen := len(raw)
-		if rawLen < 2 || raw[0] != '"' || raw[rawLen-1] != '"' {
-			return fmt.Errorf("expected a string when parsing JSON value for %s, got '%s'", jb.name, string(raw))
+		var strVal string
+		if len(raw) == 0 || raw[0] != '"' {
+			return fmt.Errorf("expected a string, got %s", describeJSON(string(raw)))
 		}
-
-		return dest.UnmarshalText(raw[1 : rawLen-1])
-	})
+		if err := json.Unmarshal(raw, &strVal); err != nil {
+			return err
+		}
+		return dest.UnmarshalText([]byte(strVal))
+	}))
 }
 
 func (jb *jsonBinder) To(dest json.Unmarshaler) *jsonBinderWithDest {
@@ -202,38 +570,113 @@ type jsonBinderWithDest struct {
 
 func (jbd *jsonBinderWithDest) BindValue() Binding {
 	return jbd.newBinding(func() error {
-		raw, err := jbd.lookup()
+		value, err := jbd.lookup()
 		if err != nil {
 			return err
 		}
 
-		return jbd.dest.UnmarshalJSON(raw)
+		if err := jbd.dest.UnmarshalJSON(value.raw); err != nil {
+			return NewSourceValueError(jbd.source.location(value), jbd.name, err)
+		}
+		value.markAllUsed()
+		return nil
 	})
 }
 
+func (jb *jsonBinder) arrayElements() (int, func(int) *jsonBinder, error) {
+	value, err := jb.lookup()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if !value.isArray() {
+		return 0, nil, jb.valueError(value, "expected an array, got %s", describeJSON(string(value.raw)))
+	}
+	// The elements are marked as used when they are bound themselves
+	value.used = true
+	elements := value.elements
+
+	return len(elements), func(elNum int) *jsonBinder {
+		name := fmt.Sprintf("%s[%d]", jb.name, elNum)
+		return &jsonBinder{
+			source: jb.source,
+			name:   name,
+			lookup: func() (*jsonValue, error) {
+				if elNum >= len(elements) {
+					return nil, fmt.Errorf(
+						"tried to access invalid element %s, array only has %d elements", name, len(elements),
+					)
+				}
+				return elements[elNum], nil
+			},
+		}
+	}, nil
+}
+
 func (jb *jsonBinder) BindArrayValueTo(length *int, element *func(int) LazySingleValueBinder) Binding {
 	return jb.newBinding(func() error {
-		raw, err := jb.lookup()
+		arrLength, getElement, err := jb.arrayElements()
+		if err != nil {
+			return err
+		}
+
+		*length = arrLength
+		*element = func(elNum int) LazySingleValueBinder {
+			return getElement(elNum)
+		}
+		return nil
+	})
+}
+
+func (jb *jsonBinder) BindObjectArrayValueTo(length *int, element *func(int) ObjectValueBinder) Binding {
+	return jb.newBinding(func() error {
+		arrLength, getElement, err := jb.arrayElements()
 		if err != nil {
 			return err
 		}
 
-		var rawArr []json.RawMessage
-		if err := json.Unmarshal(raw, &rawArr); err != nil { // TODO: better error message
+		*length = arrLength
+		*element = func(elNum int) ObjectValueBinder {
+			return getElement(elNum)
+		}
+		return nil
+	})
+}
+
+func (jb *jsonBinder) propertyPrefix() string {
+	return jb.name + "."
+}
+
+func (jb *jsonBinder) Property(name string) PropertyValueBinder {
+	return jb.From(name)
+}
+
+func (jb *jsonBinder) BindMapValueTo(keys *[]string, element *func(string) LazySingleValueBinder) Binding {
+	return jb.newBinding(func() error {
+		value, err := jb.lookup()
+		if err != nil {
 			return err
 		}
 
-		*length = len(rawArr)
-		*element = func(elNum int) LazySingleValueBinder {
-			name := fmt.Sprintf("%s[%d]", jb.name, elNum)
+		if !value.isObject() {
+			return jb.valueError(value, "expected an object, got %s", describeJSON(string(value.raw)))
+		}
+		value.used = true
+		mapKeys := append([]string(nil), value.keys...)
+		sort.Strings(mapKeys)
+
+		*keys = mapKeys
+		*element = func(key string) LazySingleValueBinder {
+			name := jb.name + "." + key
 			return &jsonBinder{
 				source: jb.source,
 				name:   name,
-				lookup: func() (json.RawMessage, error) {
-					if elNum >= len(rawArr) {
-						return nil, fmt.Errorf("tried to access invalid element %s, array only has %d elements", name, elNum)
+				lookup: func() (*jsonValue, error) {
+					el, ok := value.property(key)
+					if !ok {
+						return nil, NewBindFieldMissingError(jb.source.GetName(), name)
 					}
-					return rawArr[elNum], nil
+					return el, nil
 				},
 			}
 		}
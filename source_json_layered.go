@@ -0,0 +1,225 @@
+package croconf
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const jsonIncludeKey = "$include"
+
+// JSONDocument is a named JSON document, e.g. the contents of a config file.
+type JSONDocument struct {
+	Name string
+	Data []byte
+}
+
+// SourceLayeredJSON is a config source for multiple JSON documents, e.g. a base
+// config file and per-environment overlays, that are deep-merged in order.
+// Objects are merged property by property, with the values of the later
+// documents taking precedence, while arrays and all other values are replaced
+// by default. A null value in a later document unsets the previous value.
+//
+// Documents can also include other files with an "$include" property, which is
+// either a single path or an array of paths, relative to the path of the
+// document. The included files are merged before the document itself, so its
+// own values take precedence over theirs.
+//
+// After a binding has been applied, its Source() is the file the value came
+// from, and its GetName() is the document name or path.
+type SourceLayeredJSON struct {
+	doc          *treeDocument
+	documents    []JSONDocument
+	paths        []string
+	fsys         fs.FS
+	appendArrays bool
+}
+
+type LayeredJSONSourceOption func(*SourceLayeredJSON)
+
+// WithAppendedArrays makes the arrays in later documents get appended to the
+// arrays from the previous documents, instead of replacing them.
+func WithAppendedArrays() LayeredJSONSourceOption {
+	return func(slj *SourceLayeredJSON) {
+		slj.appendArrays = true
+	}
+}
+
+// WithJSONIncludesFrom sets the file system for the "$include" paths of the
+// documents given to NewLayeredJSONSource(). Sources created with
+// NewLayeredJSONSourceFromFS() use the same file system by default.
+func WithJSONIncludesFrom(fsys fs.FS) LayeredJSONSourceOption {
+	return func(slj *SourceLayeredJSON) {
+		slj.fsys = fsys
+	}
+}
+
+// NewLayeredJSONSource creates a source for the given documents, with the
+// later ones taking precedence over the earlier ones.
+func NewLayeredJSONSource(documents []JSONDocument, options ...LayeredJSONSourceOption) *SourceLayeredJSON {
+	slj := &SourceLayeredJSON{documents: documents}
+	slj.doc = &treeDocument{source: slj, root: newTreeObject(0, 0)}
+	for _, opt := range options {
+		opt(slj)
+	}
+	return slj
+}
+
+// NewLayeredJSONSourceFromFS creates a source for the files at the given paths,
+// with the later ones taking precedence over the earlier ones. The files are
+// read when the source is initialized.
+func NewLayeredJSONSourceFromFS(
+	fsys fs.FS, paths []string, options ...LayeredJSONSourceOption,
+) *SourceLayeredJSON {
+	slj := &SourceLayeredJSON{paths: paths, fsys: fsys}
+	slj.doc = &treeDocument{source: slj, root: newTreeObject(0, 0)}
+	for _, opt := range options {
+		opt(slj)
+	}
+	return slj
+}
+
+func (slj *SourceLayeredJSON) Initialize() error {
+	root := newTreeObject(0, 0)
+	for _, doc := range slj.documents {
+		if err := slj.mergeDocument(root, doc, nil); err != nil {
+			return err
+		}
+	}
+	for _, filePath := range slj.paths {
+		if err := slj.mergeFile(root, filePath, nil); err != nil {
+			return err
+		}
+	}
+	slj.doc.root = root
+	return nil
+}
+
+func (slj *SourceLayeredJSON) GetName() string {
+	return "layered json"
+}
+
+func (slj *SourceLayeredJSON) From(name string) *treeBinder {
+	return slj.doc.from(name)
+}
+
+func (slj *SourceLayeredJSON) FromPath(path ...string) DocumentValueBinder {
+	return slj.doc.fromPath(path)
+}
+
+// UnknownValues returns errors for all keys in the document that weren't used
+// by any of the fields.
+func (slj *SourceLayeredJSON) UnknownValues() []error {
+	return slj.doc.UnknownValues()
+}
+
+func (slj *SourceLayeredJSON) mergeFile(root *treeNode, filePath string, including []string) error {
+	for _, p := range including {
+		if p == filePath {
+			return fmt.Errorf("circular include: %s", strings.Join(append(including, filePath), " -> "))
+		}
+	}
+	data, err := fs.ReadFile(slj.fsys, filePath)
+	if err != nil {
+		return err
+	}
+	return slj.mergeDocument(root, JSONDocument{Name: filePath, Data: data}, including)
+}
+
+func (slj *SourceLayeredJSON) mergeDocument(root *treeNode, doc JSONDocument, including []string) error {
+	origin := &jsonLayerSource{name: doc.Name}
+	docRoot := newTreeObject(1, 1) // empty documents are empty objects
+	if len(bytes.TrimSpace(doc.Data)) > 0 {
+		var err error
+		if docRoot, err = jsonToTree(doc.Data, origin); err != nil {
+			return newJSONInitError(doc.Data, doc.Name, err, nil)
+		}
+	}
+
+	if include, ok := docRoot.props[jsonIncludeKey]; ok {
+		if err := slj.mergeIncludes(root, doc.Name, include, append(including, doc.Name)); err != nil {
+			return err
+		}
+		delete(docRoot.props, jsonIncludeKey)
+		docRoot.keys = removeString(docRoot.keys, jsonIncludeKey)
+	}
+
+	slj.merge(root, docRoot)
+	return nil
+}
+
+func (slj *SourceLayeredJSON) mergeIncludes(
+	root *treeNode, docName string, include *treeNode, including []string,
+) error {
+	includeErr := func(node *treeNode, format string, args ...interface{}) error {
+		return NewSourceValueError(
+			formatLocation(docName, node.line, node.column), jsonIncludeKey, fmt.Errorf(format, args...),
+		)
+	}
+
+	paths := []*treeNode{include}
+	if include.kind == treeArray {
+		paths = include.elements
+	}
+	for _, node := range paths {
+		includePath, ok := node.value.(string)
+		if !ok {
+			return includeErr(node, "expected a file path, got %s", node.describe())
+		}
+		if slj.fsys == nil {
+			return includeErr(node, "can't include %s without a file system", includePath)
+		}
+		if !path.IsAbs(includePath) {
+			includePath = path.Join(path.Dir(docName), includePath)
+		}
+		if err := slj.mergeFile(root, includePath, including); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge merges the src object into the dst object.
+func (slj *SourceLayeredJSON) merge(dst, src *treeNode) {
+	for _, key := range src.keys {
+		srcVal, dstVal := src.props[key], dst.props[key]
+		switch {
+		case dstVal == nil:
+			dst.set(key, srcVal)
+		case srcVal.kind == treeObject && dstVal.kind == treeObject:
+			slj.merge(dstVal, srcVal)
+		case srcVal.kind == treeArray && dstVal.kind == treeArray && slj.appendArrays:
+			merged := *srcVal
+			merged.elements = append(append([]*treeNode(nil), dstVal.elements...), srcVal.elements...)
+			dst.set(key, &merged)
+		default:
+			dst.set(key, srcVal)
+		}
+	}
+}
+
+func removeString(list []string, str string) []string {
+	result := list[:0]
+	for _, s := range list {
+		if s != str {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// jsonLayerSource is the Source of the values from a single document of a
+// SourceLayeredJSON.
+type jsonLayerSource struct {
+	name string
+}
+
+func (jls *jsonLayerSource) Initialize() error {
+	return nil // the parent source is initialized instead
+}
+
+func (jls *jsonLayerSource) GetName() string {
+	return jls.name
+}
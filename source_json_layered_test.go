@@ -0,0 +1,213 @@
+package croconf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLayeredJSONMerge(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"config/base.json": {Data: []byte(`{
+	"vus": 10,
+	"duration": "1m",
+	"hosts": ["example.com"],
+	"tags": {"env": "base", "team": "perf"},
+	"userAgent": "croconf",
+	"maxRedirects": 5
+}`)},
+		"config/prod.json": {Data: []byte(`{
+	"$include": "common/limits.json",
+	"duration": "10m",
+	"hosts": ["k6.io"],
+	"tags": {"env": "prod"},
+	"userAgent": null
+}`)},
+		"config/common/limits.json": {Data: []byte(`{"vus": 100, "rps": 50}`)},
+	}
+
+	testCases := []struct {
+		name     string
+		options  []LayeredJSONSourceOption
+		expHosts []string
+	}{
+		{"replaced arrays", nil, []string{"k6.io"}},
+		{"appended arrays", []LayeredJSONSourceOption{WithAppendedArrays()}, []string{"example.com", "k6.io"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			source := NewLayeredJSONSourceFromFS(fsys, []string{"config/base.json", "config/prod.json"}, tc.options...)
+
+			var vus, rps int64
+			var maxRedirects int32
+			var env testMode
+			var duration time.Duration
+			var hosts []string
+			var tags map[string]string
+			userAgent := "default"
+
+			cm := NewManager()
+			vusField := cm.AddField(NewInt64Field(&vus, source.From("vus")))
+			rpsField := cm.AddField(NewInt64Field(&rps, source.From("rps")))
+			// these fields wrap the bindings of the source
+			redirectsField := cm.AddField(NewInt32Field(&maxRedirects, source.From("maxRedirects")))
+			envField := cm.AddField(NewTypedEnumField(&env, []testMode{"base", "prod"}, source.From("tags").From("env")))
+			durationField := cm.AddField(NewDurationField(&duration, source.From("duration")))
+			cm.AddField(NewStringSliceField(&hosts, source.From("hosts")))
+			cm.AddField(NewStringMapField(&tags, source.From("tags")))
+			userAgentField := cm.AddField(NewStringField(&userAgent, source.From("userAgent")))
+			if err := cm.Consolidate(); err != nil {
+				t.Fatalf("unexpected consolidation error %s", err)
+			}
+
+			if vus != 100 || rps != 50 || duration != 10*time.Minute || userAgent != "default" {
+				t.Errorf("unexpected values %d %d %s %s", vus, rps, duration, userAgent)
+			}
+			if maxRedirects != 5 || env != "prod" {
+				t.Errorf("unexpected values %d %s", maxRedirects, env)
+			}
+			if !reflect.DeepEqual(hosts, tc.expHosts) {
+				t.Errorf("expected hosts %v, got %v", tc.expHosts, hosts)
+			}
+			if exp := map[string]string{"env": "prod", "team": "perf"}; !reflect.DeepEqual(tags, exp) {
+				t.Errorf("expected tags %v, got %v", exp, tags)
+			}
+
+			expSources := map[*ManagedField]string{
+				vusField:       "config/common/limits.json",
+				rpsField:       "config/common/limits.json",
+				durationField:  "config/prod.json",
+				redirectsField: "config/base.json",
+				envField:       "config/prod.json",
+			}
+			for field, exp := range expSources {
+				if name := field.LastBindingFromSource().Source().GetName(); name != exp {
+					t.Errorf("expected %s to be from %s, got %s", field.Name, exp, name)
+				}
+			}
+			if userAgentField.HasBeenSetFromSource() {
+				t.Errorf("expected the userAgent to be unset by the null value")
+			}
+		})
+	}
+}
+
+func TestLayeredJSONDocuments(t *testing.T) {
+	t.Parallel()
+
+	source := NewLayeredJSONSource([]JSONDocument{
+		{Name: "base.json", Data: []byte(`{"vus": 10, "dns": {"ttl": "5m", "server": "8.8.8.8"}}`)},
+		{Name: "override.json", Data: []byte(`{
+	"dns": {
+		"ttl": "forever"
+	}
+}`)},
+	})
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var vus int64
+	var server string
+	var ttl time.Duration
+	binding := source.From("vus").BindIntValueTo(&vus)
+	if bfs := binding.(BindingFromSource); bfs.Source() != source {
+		t.Errorf("expected the layered source before the binding is applied, got %s", bfs.Source().GetName())
+	}
+	if err := binding.Apply(); err != nil || vus != 10 {
+		t.Errorf("expected 10 VUs, got %d (error: %s)", vus, err)
+	}
+	if name := binding.(BindingFromSource).Source().GetName(); name != "base.json" {
+		t.Errorf("expected the value to be from base.json, got %s", name)
+	}
+	var vus32 int32
+	var vusTyped testVUs
+	for _, field := range []Field{NewInt32Field(&vus32, source.From("vus")), NewSignedField(&vusTyped, source.From("vus"))} {
+		wrapped := field.Bindings()[0]
+		if err := wrapped.Apply(); err != nil {
+			t.Fatalf("unexpected error %s", err)
+		}
+		if name := wrapped.(BindingFromSource).Source().GetName(); name != "base.json" {
+			t.Errorf("expected the wrapped value to be from base.json, got %s", name)
+		}
+	}
+	if vus32 != 10 || vusTyped != 10 {
+		t.Errorf("expected 10 VUs, got %d and %d", vus32, vusTyped)
+	}
+	if err := source.From("dns").From("server").BindStringValueTo(&server).Apply(); err != nil || server != "8.8.8.8" {
+		t.Errorf("expected server 8.8.8.8, got %s (error: %s)", server, err)
+	}
+
+	expErr := `override.json:3:10: dns.ttl: expected a duration, got "forever"`
+	if err := source.From("dns").From("ttl").BindDurationValueTo(&ttl, time.Second).Apply(); err == nil ||
+		err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+	expErr = "field missing is missing in config source layered json"
+	if err := source.From("missing").BindIntValueTo(&vus).Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	// unknown keys point to the document they came from
+	unknown := source.UnknownValues()
+	expUnknown := "override.json:3:10: unknown key dns.ttl"
+	if len(unknown) != 1 || !strings.HasPrefix(unknown[0].Error(), expUnknown) {
+		t.Errorf("expected the unknown key '%s', got %q", expUnknown, unknown)
+	}
+}
+
+func TestLayeredJSONErrors(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"a.json":        {Data: []byte(`{"$include": "b.json"}`)},
+		"b.json":        {Data: []byte(`{"$include": ["c.json", "a.json"]}`)},
+		"c.json":        {Data: []byte(`{}`)},
+		"invalid.json":  {Data: []byte("{\n  \"vus\": 10,\n  \"rps\" 5\n}")},
+		"number.json":   {Data: []byte(`{"vus": 01}`)},
+		"string.json":   {Data: []byte(`{"vus": "\x"}`)},
+		"array.json":    {Data: []byte(`[1, 2]`)},
+		"trailing.json": {Data: []byte(`{"vus": 1},`)},
+		"include.json":  {Data: []byte(`{"$include": 5}`)},
+	}
+
+	testCases := []struct {
+		path   string
+		expErr string
+	}{
+		{"a.json", "circular include: a.json -> b.json -> a.json"},
+		{"invalid.json", "invalid.json:3:9: invalid character '5' after object key"},
+		{"number.json", "number.json:1:10: invalid character '1' after object key:value pair"},
+		{"string.json", "string.json:1:11: invalid escape sequence `\\x` in string"},
+		{"array.json", "array.json:1:1: expected an object, got an array"},
+		{"trailing.json", "trailing.json:1:12: unexpected content after the end of the document"},
+		{"include.json", "include.json:1:14: $include: expected a file path, got 5"},
+		{"missing.json", "open missing.json: file does not exist"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.path, func(t *testing.T) {
+			t.Parallel()
+			// Only check the first line, parse errors also show the invalid line
+			err := NewLayeredJSONSourceFromFS(fsys, []string{tc.path}).Initialize()
+			if err == nil || strings.SplitN(err.Error(), "\n", 2)[0] != tc.expErr {
+				t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+			}
+		})
+	}
+
+	expErr := "base.json:1:14: $include: can't include other.json without a file system"
+	source := NewLayeredJSONSource([]JSONDocument{{Name: "base.json", Data: []byte(`{"$include": "other.json"}`)}})
+	if err := source.Initialize(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
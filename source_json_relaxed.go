@@ -0,0 +1,255 @@
+package croconf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// relaxedJSONConverter converts relaxed JSON documents, with comments, trailing
+// commas, unquoted object keys and single-quoted strings, to standard JSON. It
+// keeps track of the places where the converted document differs in length
+// from the original, so offsets in it can be mapped back to the original text.
+type relaxedJSONConverter struct {
+	in  []byte
+	i   int
+	out []byte
+
+	// shifts are the offsets in the output after which the difference between
+	// the output and the input offsets changes
+	shifts []relaxedJSONShift
+
+	lastComma int // the output offset of the last comma, or -1
+}
+
+type relaxedJSONShift struct {
+	outOffset, inOffset int
+}
+
+// convertRelaxedJSON returns the converted document and a function that maps
+// offsets in it to offsets in the original document.
+func convertRelaxedJSON(data []byte) ([]byte, func(int) int, error) {
+	c := &relaxedJSONConverter{in: data, out: make([]byte, 0, len(data)), lastComma: -1}
+	for c.i < len(c.in) {
+		if err := c.next(); err != nil {
+			return nil, nil, err
+		}
+	}
+	return c.out, c.originalOffset, nil
+}
+
+// originalOffset maps an offset in the converted document to an offset in the
+// original relaxed document.
+func (c *relaxedJSONConverter) originalOffset(outOffset int) int {
+	idx := sort.Search(len(c.shifts), func(i int) bool { return c.shifts[i].outOffset > outOffset })
+	if idx == 0 {
+		return outOffset
+	}
+	shift := c.shifts[idx-1]
+	return shift.inOffset + outOffset - shift.outOffset
+}
+
+// shift records that the next output offset corresponds to the given input one.
+func (c *relaxedJSONConverter) shift(inOffset int) {
+	c.shifts = append(c.shifts, relaxedJSONShift{outOffset: len(c.out), inOffset: inOffset})
+}
+
+func (c *relaxedJSONConverter) errorAt(offset int, msg string) error {
+	return &jsonOffsetError{offset: offset, err: errors.New(msg)}
+}
+
+func (c *relaxedJSONConverter) next() error {
+	switch ch := c.in[c.i]; {
+	case ch == '/' && c.i+1 < len(c.in) && (c.in[c.i+1] == '/' || c.in[c.i+1] == '*'):
+		return c.skipComment()
+	case ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n':
+		c.out = append(c.out, ch)
+		c.i++
+		return nil
+	case (ch == '}' || ch == ']') && c.lastComma >= 0:
+		c.out[c.lastComma] = ' ' // a trailing comma
+	}
+
+	c.lastComma = -1
+	switch ch := c.in[c.i]; {
+	case ch == ',':
+		// Only commas after values can be trailing commas, so e.g. [,] is
+		// still invalid
+		if prev := c.lastOutputChar(); prev != 0 && prev != '[' && prev != '{' && prev != ',' && prev != ':' {
+			c.lastComma = len(c.out)
+		}
+		c.out = append(c.out, ch)
+		c.i++
+	case ch == '"':
+		return c.copyString()
+	case ch == '\'':
+		return c.convertSingleQuotedString()
+	case isJSONIdentifierChar(ch, true):
+		c.convertIdentifier()
+	default:
+		c.out = append(c.out, ch)
+		c.i++
+	}
+	return nil
+}
+
+// lastOutputChar returns the last character of the output that isn't
+// whitespace, or 0 if there is none. Comments are replaced with spaces, so they
+// are skipped too.
+func (c *relaxedJSONConverter) lastOutputChar() byte {
+	for i := len(c.out) - 1; i >= 0; i-- {
+		if ch := c.out[i]; ch != ' ' && ch != '\t' && ch != '\r' && ch != '\n' {
+			return ch
+		}
+	}
+	return 0
+}
+
+// skipComment replaces comments with spaces, keeping the new lines, so that the
+// line and column numbers in the converted document stay the same.
+func (c *relaxedJSONConverter) skipComment() error {
+	start := c.i
+	end := len(c.in)
+	if c.in[c.i+1] == '*' {
+		idx := bytes.Index(c.in[c.i+2:], []byte("*/"))
+		if idx < 0 {
+			return c.errorAt(start, "unterminated comment")
+		}
+		end = c.i + 2 + idx + 2
+	} else if idx := bytes.IndexByte(c.in[c.i:], '\n'); idx >= 0 {
+		end = c.i + idx
+	}
+
+	for ; c.i < end; c.i++ {
+		if c.in[c.i] == '\n' {
+			c.out = append(c.out, '\n')
+		} else {
+			c.out = append(c.out, ' ')
+		}
+	}
+	return nil
+}
+
+func (c *relaxedJSONConverter) copyString() error {
+	start := c.i
+	for c.i++; c.i < len(c.in); c.i++ {
+		switch c.in[c.i] {
+		case '\\':
+			c.i++
+		case '"':
+			c.i++
+			c.out = append(c.out, c.in[start:c.i]...)
+			return nil
+		}
+	}
+	return c.errorAt(start, "unterminated string")
+}
+
+// convertSingleQuotedString converts 'strings' to "strings", escaping any
+// double quotes in them and unescaping any escaped single quotes.
+func (c *relaxedJSONConverter) convertSingleQuotedString() error {
+	start := c.i
+	c.out = append(c.out, '"')
+	for c.i++; c.i < len(c.in); c.i++ {
+		switch ch := c.in[c.i]; {
+		case ch == '\'':
+			c.i++
+			c.out = append(c.out, '"')
+			return nil
+		case ch == '"':
+			c.out = append(c.out, '\\', '"')
+			c.shift(c.i + 1)
+		case ch == '\\' && c.i+1 < len(c.in) && c.in[c.i+1] == '\'':
+			c.i++
+			c.out = append(c.out, '\'')
+			c.shift(c.i + 1)
+		case ch == '\\' && c.i+1 < len(c.in):
+			c.out = append(c.out, ch, c.in[c.i+1])
+			c.i++
+		default:
+			c.out = append(c.out, ch)
+		}
+	}
+	return c.errorAt(start, "unterminated string")
+}
+
+func isJSONIdentifierChar(c byte, first bool) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$' || (!first && c >= '0' && c <= '9')
+}
+
+// convertIdentifier quotes identifiers that are used as object keys. All other
+// identifiers, e.g. true, false and null, are kept as they are.
+func (c *relaxedJSONConverter) convertIdentifier() {
+	start := c.i
+	for c.i < len(c.in) && isJSONIdentifierChar(c.in[c.i], c.i == start) {
+		c.i++
+	}
+	identifier := c.in[start:c.i]
+
+	// Look for the colon after the identifier, skipping any whitespace or comments
+	next := c.i
+	for next < len(c.in) {
+		switch {
+		case bytes.IndexByte([]byte(" \t\r\n"), c.in[next]) >= 0:
+			next++
+			continue
+		case bytes.HasPrefix(c.in[next:], []byte("/*")):
+			if idx := bytes.Index(c.in[next+2:], []byte("*/")); idx >= 0 {
+				next += 2 + idx + 2
+				continue
+			}
+		case bytes.HasPrefix(c.in[next:], []byte("//")):
+			if idx := bytes.IndexByte(c.in[next:], '\n'); idx >= 0 {
+				next += idx
+				continue
+			}
+		}
+		break
+	}
+
+	if next >= len(c.in) || c.in[next] != ':' {
+		c.out = append(c.out, identifier...)
+		return
+	}
+	c.out = append(c.out, '"')
+	c.shift(start)
+	c.out = append(c.out, identifier...)
+	c.out = append(c.out, '"')
+	c.shift(c.i)
+}
+
+// relaxedJSONError fixes the syntax errors of encoding/json for the converted
+// document, which quote the invalid character, when that character was changed
+// by the conversion, e.g. the quotes that were added around unquoted keys.
+func relaxedJSONError(err error, converted, original []byte, originalOffset func(int) int) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Offset <= 0 {
+		return err
+	}
+	offset := int(syntaxErr.Offset) - 1
+	origOffset := originalOffset(offset)
+	if offset >= len(converted) || origOffset >= len(original) || converted[offset] == original[origOffset] {
+		return err
+	}
+	msg := strings.Replace(
+		err.Error(), "invalid character "+quoteJSONChar(converted[offset]),
+		"invalid character "+quoteJSONChar(original[origOffset]), 1,
+	)
+	return &jsonOffsetError{offset: offset, err: errors.New(msg)}
+}
+
+// quoteJSONChar quotes characters the same way encoding/json does it in its
+// syntax errors.
+func quoteJSONChar(ch byte) string {
+	switch ch {
+	case '\'':
+		return `'\''`
+	case '"':
+		return `'"'`
+	}
+	s := strconv.Quote(string(ch))
+	return "'" + s[1:len(s)-1] + "'"
+}
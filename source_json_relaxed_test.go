@@ -0,0 +1,98 @@
+package croconf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRelaxedJSONParse(t *testing.T) {
+	t.Parallel()
+
+	json := `
+// a comment
+{
+	vus: 10, /* an inline
+	multiline comment */
+	'userAgent': 'croconf "test" v1',
+	$quote: 'it\'s',
+	"url": "http://k6.io//path", // comments are not detected in strings
+	hosts: ['example.com', 'k6.io',],
+	dns: {
+		server: "8.8.8.8", // trailing comma
+	},
+	throw: true,
+	rps: 1e3,
+}
+`
+	source := NewJSONSource([]byte(json), WithRelaxedJSON())
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var vus int64
+	var rps float64
+	var throw bool
+	var userAgent, quote, url, server string
+	var hosts []string
+
+	cm := NewManager()
+	cm.AddField(NewInt64Field(&vus, source.From("vus")))
+	cm.AddField(NewFloat64Field(&rps, source.From("rps")))
+	cm.AddField(NewBoolField(&throw, source.From("throw")))
+	cm.AddField(NewStringField(&userAgent, source.From("userAgent")))
+	cm.AddField(NewStringField(&quote, source.From("$quote")))
+	cm.AddField(NewStringField(&url, source.From("url")))
+	cm.AddField(NewStringField(&server, source.From("dns").From("server")))
+	cm.AddField(NewStringSliceField(&hosts, source.From("hosts")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+
+	if vus != 10 || rps != 1000 || !throw {
+		t.Errorf("unexpected values %d %g %t", vus, rps, throw)
+	}
+	if userAgent != `croconf "test" v1` || quote != "it's" || url != "http://k6.io//path" || server != "8.8.8.8" {
+		t.Errorf("unexpected values %q %q %q %q", userAgent, quote, url, server)
+	}
+	if exp := []string{"example.com", "k6.io"}; !reflect.DeepEqual(hosts, exp) {
+		t.Errorf("expected hosts %v, got %v", exp, hosts)
+	}
+}
+
+func TestRelaxedJSONParseErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		json   string
+		expErr string
+	}{
+		{"{\n  vus: 10,\n  rps 5\n}", "json:3:3: invalid character 'r' looking for beginning of value"},
+		{"{\n  'agent': 'a \"b\"' 'c'\n}", `json:2:20: invalid character '\'' after object key:value pair`},
+		{"{a: 1 b: 2}", "json:1:7: invalid character 'b' after object key:value pair"},
+		{"{a: [,]}", "json:1:6: invalid character ',' looking for beginning of value"},
+		{"{a: [1,,]}", "json:1:8: invalid character ',' looking for beginning of value"},
+		{"{,}", "json:1:2: invalid character ',' looking for beginning of value"},
+		{"{\n  vus: 10 /* comment", "json:2:11: unterminated comment"},
+		{"{\n  agent: 'foo", "json:2:10: unterminated string"},
+		{"{vus: [1,, 2]}", "json:1:10: invalid character ',' looking for beginning of value"},
+		{"{vus: 10,} // comment\n}", "json:2:1: unexpected content after the end of the document"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.json, func(t *testing.T) {
+			t.Parallel()
+			err := NewJSONSource([]byte(tc.json), WithRelaxedJSON()).Initialize()
+			// only check the first line, without the snippet of the document
+			if err == nil || strings.SplitN(err.Error(), "\n", 2)[0] != tc.expErr {
+				t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+			}
+		})
+	}
+
+	expErr := "json:1:2: invalid character 'v' looking for beginning of value\n    {vus: 10}\n     ^"
+	if err := NewJSONSource([]byte("{vus: 10}")).Initialize(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
@@ -1,8 +1,13 @@
 package croconf
 
 import (
+	"encoding/json"
+	"fmt"
 	"math"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestJSONBindIntValue(t *testing.T) {
@@ -42,8 +47,7 @@ func TestJSONBindIntValue(t *testing.T) {
 	if err == nil {
 		t.Error("BindIntValue: expected syntax error")
 	}
-	// TODO why are double quotes "\"foo"\"" ?
-	if err.Error() != `BindIntValue: parsing "\"foo\"": invalid syntax` {
+	if err.Error() != `json:1:67: k6_user_agent: expected an integer, got "foo"` {
 		t.Error("BindIntValue: unexpected error message:", err)
 	}
 }
@@ -85,8 +89,7 @@ func TestJSONBindUintValue(t *testing.T) {
 	if err == nil {
 		t.Error("BindUintValue: expected syntax error")
 	}
-	// TODO why are double quotes "\"foo"\"" ?
-	if err.Error() != `BindIntValue: parsing "\"foo\"": invalid syntax` {
+	if err.Error() != `json:1:67: k6_user_agent: expected an unsigned integer, got "foo"` {
 		t.Error("BindIntValue: unexpected error message:", err)
 	}
 }
@@ -141,8 +144,7 @@ func TestJSONFloatValue(t *testing.T) {
 	if err == nil {
 		t.Error("BindFloatValue: expected syntax error")
 	}
-	// TODO why are double quotes "\"foo"\"" ?
-	if err.Error() != `BindIntValue: parsing "\"foo\"": invalid syntax` {
+	if err.Error() != `json:1:67: k6_user_agent: expected a number, got "foo"` {
 		t.Error("BindIntValue: unexpected error message:", err)
 	}
 }
@@ -184,8 +186,271 @@ func TestJSONBindIntValue__NestedJSON(t *testing.T) {
 	if err == nil {
 		t.Error("BindIntValue: expected syntax error")
 	}
-	// TODO why are double quotes "\"foo"\"" ?
-	if err.Error() != `BindIntValue: parsing "\"foo\"": invalid syntax` {
+	if err.Error() != `json:1:75: data.k6_user_agent: expected an integer, got "foo"` {
 		t.Error("BindIntValue: unexpected error message:", err)
 	}
 }
+
+func TestJSONErrorPositions(t *testing.T) {
+	t.Parallel()
+
+	json := []byte(`{
+	"vus": "foo",
+	"dns": {
+		"ttl": true,
+		"server": 8
+	},
+	"hosts": ["k6.io", 443],
+	"tags": "foo"
+}`)
+	source := NewJSONSource(json, WithJSONFileName("config.json"))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var intVal int64
+	var strVal string
+	var duration time.Duration
+	var hosts []string
+	var tags map[string]string
+	testCases := []struct {
+		binding Binding
+		expErr  string
+	}{
+		{source.From("vus").BindIntValueTo(&intVal), `config.json:2:9: vus: expected an integer, got "foo"`},
+		{source.From("dns").From("ttl").BindDurationValueTo(&duration, time.Second), `config.json:4:10: dns.ttl: expected a string or a number, got true`},
+		{source.From("dns").From("server").BindStringValueTo(&strVal), `config.json:5:13: dns.server: expected a string, got 8`},
+		{source.From("vus").From("foo").BindIntValueTo(&intVal), `config.json:2:9: vus: expected an object, got "foo"`},
+		{NewStringSliceField(&hosts, source.From("hosts")).Bindings()[0], `config.json:7:21: hosts[1]: expected a string, got 443`},
+		{NewStringMapField(&tags, source.From("tags")).Bindings()[0], `config.json:8:10: tags: expected an object, got "foo"`},
+	}
+	for _, tc := range testCases {
+		if err := tc.binding.Apply(); err == nil || err.Error() != tc.expErr {
+			t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+		}
+	}
+
+	initErrors := []struct {
+		json   string
+		expErr string
+	}{
+		{"{\n\t\"vus\": 10,\n\t\"rps\" 5\n}", "config.json:3:8: invalid character '5' after object key\n    \t\"rps\" 5\n    \t      ^"},
+		{`["vus"]`, "config.json:1:1: expected an object, got an array\n    [\"vus\"]\n    ^"},
+		{`{"vus": 10`, "config.json:1:10: unexpected end of JSON input\n    {\"vus\": 10\n             ^"},
+	}
+	for _, tc := range initErrors {
+		err := NewJSONSource([]byte(tc.json), WithJSONFileName("config.json")).Initialize()
+		if err == nil || err.Error() != tc.expErr {
+			t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+		}
+	}
+}
+
+func TestJSONNullParents(t *testing.T) {
+	t.Parallel()
+
+	for _, doc := range []string{`{"dns": null}`, `null`, ` null `} {
+		source := NewJSONSource([]byte(doc))
+		var ttl int64
+		cm := NewManager()
+		cm.AddField(NewInt64Field(&ttl, DefaultIntValue(5), source.From("dns").From("ttl")))
+		if err := cm.Consolidate(); err != nil {
+			t.Errorf("%s: unexpected consolidation error %s", doc, err)
+		}
+		if ttl != 5 {
+			t.Errorf("%s: expected the default value 5, got %d", doc, ttl)
+		}
+		if cm.Field(&ttl).HasBeenSetFromSource() {
+			t.Errorf("%s: expected the value to not be set from the JSON source", doc)
+		}
+	}
+}
+
+func TestJSONUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	json := []byte(`{
+	"vsu": 10,
+	"dns": {
+		"tll": 5,
+		"server": "8.8.8.8",
+		"foo": "bar"
+	},
+	"dsn": {"ttl": 1},
+	"tags": {"a": "b"},
+	"targets": [{"url": "a"}, {"url": "b", "maxRetires": 1}]
+}`)
+
+	newManager := func(options ...ManagerOption) (*Manager, *SourceJSON) {
+		source := NewJSONSource(json, WithJSONFileName("config.json"))
+		var vus, ttl int64
+		var server string
+		var tags map[string]string
+		var targets []testTarget
+
+		cm := NewManager(options...)
+		cm.AddField(NewInt64Field(&vus, source.From("vus")))
+		cm.AddField(NewInt64Field(&ttl, source.From("dns").From("ttl")))
+		cm.AddField(NewStringField(&server, source.From("dns").From("server")))
+		cm.AddField(NewStringMapField(&tags, source.From("tags")))
+		cm.AddField(NewStructSliceField(
+			&targets,
+			func(target *testTarget, el ObjectValueBinder, cm *Manager) {
+				cm.AddField(NewStringField(&target.URL, el.Property("url")))
+				cm.AddField(NewInt64Field(&target.MaxRetries, el.Property("maxRetries")))
+			},
+			source.From("targets"),
+		))
+		return cm, source
+	}
+
+	expWarnings := []string{
+		"config.json:2:9: unknown key vsu, did you mean vus?",
+		"config.json:4:10: unknown key dns.tll, did you mean dns.ttl?",
+		"config.json:6:10: unknown key dns.foo",
+		"config.json:8:9: unknown key dsn, did you mean dns?",
+		"config.json:10:55: unknown key targets[1].maxRetires, did you mean targets[1].maxRetries?",
+	}
+
+	cm, _ := newManager()
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+	warnings := make([]string, 0, len(cm.Warnings()))
+	for _, w := range cm.Warnings() {
+		warnings = append(warnings, w.Error())
+	}
+	if !reflect.DeepEqual(warnings, expWarnings) {
+		t.Errorf("expected warnings %q, got %q", expWarnings, warnings)
+	}
+
+	cm, _ = newManager(WithStrictUnknownValues())
+	expErr := "Unknown config values: \n\t- " + strings.Join(expWarnings, "\n\t- ")
+	if err := cm.Consolidate(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	// the whole value is used by Lookup()
+	source := NewJSONSource([]byte(`{"scenarios": {"foo": {"bar": 1}}, "other": 2}`))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+	if _, ok := source.Lookup("scenarios"); !ok {
+		t.Errorf("expected scenarios to be present")
+	}
+	unknown := source.UnknownValues()
+	if len(unknown) != 1 || unknown[0].Error() != "json:1:45: unknown key other" {
+		t.Errorf("unexpected unknown values %q", unknown)
+	}
+
+	// values that fail to bind aren't marked as used
+	source = NewJSONSource([]byte(`{"vus": "ten"}`))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+	var vus int64
+	if err := source.From("vus").BindIntValueTo(&vus).Apply(); err == nil {
+		t.Errorf("expected an error when binding vus")
+	}
+	unknown = source.UnknownValues()
+	if len(unknown) != 1 || !strings.HasPrefix(unknown[0].Error(), "json:1:9: unknown key vus") {
+		t.Errorf("unexpected unknown values %q", unknown)
+	}
+}
+
+// nestedJSON generates a document with width properties on each level, nested
+// depth levels deep, with integers for the leaf values. It also returns the
+// paths of all the leaf values.
+func nestedJSON(depth, width int) (string, [][]string) {
+	if depth == 0 {
+		return "1", [][]string{nil}
+	}
+	child, childPaths := nestedJSON(depth-1, width)
+	props := make([]string, width)
+	var paths [][]string
+	for i := range props {
+		key := fmt.Sprintf("p%d", i)
+		props[i] = fmt.Sprintf("%q: %s", key, child)
+		for _, childPath := range childPaths {
+			paths = append(paths, append([]string{key}, childPath...))
+		}
+	}
+	return "{" + strings.Join(props, ", ") + "}", paths
+}
+
+func BenchmarkJSONNestedLookups(b *testing.B) {
+	for _, depth := range []int{2, 4, 6} {
+		data, paths := nestedJSON(depth, 3)
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				source := NewJSONSource([]byte(data))
+				if err := source.Initialize(); err != nil {
+					b.Fatal(err)
+				}
+				var val int64
+				for _, path := range paths {
+					binder := source.From(path[0])
+					for _, name := range path[1:] {
+						binder = binder.From(name)
+					}
+					if err := binder.BindIntValueTo(&val).Apply(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkJSONNestedLookupsReparse is the baseline for
+// BenchmarkJSONNestedLookups, it re-parses the parent objects of every value,
+// like SourceJSON did before the document was indexed at initialization.
+func BenchmarkJSONNestedLookupsReparse(b *testing.B) {
+	for _, depth := range []int{2, 4, 6} {
+		data, paths := nestedJSON(depth, 3)
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				var val int64
+				for _, path := range paths {
+					raw := json.RawMessage(data)
+					for _, name := range path {
+						var props map[string]json.RawMessage
+						if err := json.Unmarshal(raw, &props); err != nil {
+							b.Fatal(err)
+						}
+						raw = props[name]
+					}
+					if err := json.Unmarshal(raw, &val); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkJSONObjectArray(b *testing.B) {
+	for _, count := range []int{100, 1000} {
+		targets := make([]string, count)
+		for i := range targets {
+			targets[i] = fmt.Sprintf(`{"url": "https://k6.io/%d", "maxRetries": %d, "tags": ["a", "b"]}`, i, i)
+		}
+		data := []byte(`{"targets": [` + strings.Join(targets, ", ") + `]}`)
+
+		b.Run(fmt.Sprintf("count=%d", count), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				source := NewJSONSource(data)
+				var dest []testTarget
+				cm := NewManager()
+				cm.AddField(NewStructSliceField(&dest, func(el *testTarget, binder ObjectValueBinder, cm *Manager) {
+					cm.AddField(NewStringField(&el.URL, binder.Property("url")))
+					cm.AddField(NewInt64Field(&el.MaxRetries, binder.Property("maxRetries")))
+					cm.AddField(NewStringSliceField(&el.Tags, binder.Property("tags")))
+				}, source.From("targets")))
+				if err := cm.Consolidate(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package croconf
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+// jsonTreeConverter converts the values of an indexed JSON document to
+// treeNode values, for sources that need to combine them with the values of
+// other documents, e.g. SourceLayeredJSON.
+type jsonTreeConverter struct {
+	data       []byte
+	lineStarts []int
+	origin     Source // set as the origin of all converted nodes
+}
+
+// jsonToTree indexes the JSON document with indexJSON() and converts it to a
+// tree, keeping the positions of all values, so they can be used in errors.
+func jsonToTree(data []byte, origin Source) (*treeNode, error) {
+	root, err := indexJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	c := &jsonTreeConverter{data: data, lineStarts: []int{0}, origin: origin}
+	for i, b := range data {
+		if b == '\n' {
+			c.lineStarts = append(c.lineStarts, i+1)
+		}
+	}
+	return c.convert(root), nil
+}
+
+func (c *jsonTreeConverter) convert(value *jsonValue) *treeNode {
+	line := sort.Search(len(c.lineStarts), func(i int) bool { return c.lineStarts[i] > value.offset })
+	node := &treeNode{line: line, column: value.offset - c.lineStarts[line-1] + 1, origin: c.origin}
+
+	switch {
+	case value.isObject():
+		node.kind = treeObject
+		node.props = make(map[string]*treeNode, len(value.keys))
+		for _, key := range value.keys {
+			node.set(key, c.convert(value.props[key]))
+		}
+	case value.isArray():
+		node.kind = treeArray
+		node.elements = make([]*treeNode, len(value.elements))
+		for i, el := range value.elements {
+			node.elements[i] = c.convert(el)
+		}
+	case value.isNull():
+		node.kind = treeNull
+	default:
+		node.kind = treeScalar
+		node.raw = string(value.raw)
+		node.value = jsonScalarValue(value.raw)
+	}
+	return node
+}
+
+// jsonScalarValue returns the Go value of a JSON string, bool or number that
+// was already validated by indexJSON(). Integers are int64 or uint64 values if
+// they fit in them, all other numbers are float64 values.
+func jsonScalarValue(raw json.RawMessage) interface{} {
+	switch raw[0] {
+	case '"':
+		var str string
+		_ = json.Unmarshal(raw, &str) // it's a valid string, the decoder checked it
+		return str
+	case 't', 'f':
+		return raw[0] == 't'
+	}
+	if intVal, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+		return intVal
+	}
+	if uintVal, err := strconv.ParseUint(string(raw), 10, 64); err == nil {
+		return uintVal
+	}
+	floatVal, _ := strconv.ParseFloat(string(raw), 64)
+	return floatVal
+}
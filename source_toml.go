@@ -0,0 +1,616 @@
+package croconf
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SourceTOML is a config source for TOML v1.0.0 documents. Integers, floats,
+// booleans and datetimes keep their native TOML types, so they are bound to
+// their destinations without being converted to strings first.
+type SourceTOML struct {
+	doc  *treeDocument
+	init func() error
+}
+
+type TOMLSourceOption func(*SourceTOML)
+
+// WithTOMLFileName sets the file name that is used in the parse and value
+// error messages, instead of the generic "toml" source name.
+func WithTOMLFileName(fileName string) TOMLSourceOption {
+	return func(st *SourceTOML) {
+		st.doc.fileName = fileName
+	}
+}
+
+func NewTOMLSource(data []byte, options ...TOMLSourceOption) *SourceTOML {
+	st := &SourceTOML{}
+	st.doc = &treeDocument{source: st}
+	for _, opt := range options {
+		opt(st)
+	}
+
+	st.init = func() error {
+		return st.doc.init(func() (*treeNode, error) {
+			return parseTOML(string(data))
+		})
+	}
+	return st
+}
+
+func (st *SourceTOML) Initialize() error {
+	return st.init()
+}
+
+func (st *SourceTOML) GetName() string {
+	return "toml"
+}
+
+func (st *SourceTOML) From(name string) *treeBinder {
+	return st.doc.from(name)
+}
+
+func (st *SourceTOML) FromPath(path ...string) DocumentValueBinder {
+	return st.doc.fromPath(path)
+}
+
+// UnknownValues returns errors for all keys in the document that weren't used
+// by any of the fields.
+func (st *SourceTOML) UnknownValues() []error {
+	return st.doc.UnknownValues()
+}
+
+type tomlParser struct {
+	s          string
+	i          int
+	lineStarts []int
+
+	root    *treeNode
+	current *treeNode
+
+	// TOML doesn't allow tables to be defined more than once, or to be
+	// extended in some other ways, so we need to track how they were created.
+	explicit    map[*treeNode]bool // defined by a [table] header
+	dotted      map[*treeNode]bool // defined by a dotted key, e.g. a.b = 1
+	frozen      map[*treeNode]bool // inline tables, e.g. a = {b = 1}
+	tableArrays map[*treeNode]bool // defined by [[array]] headers
+}
+
+func parseTOML(data string) (*treeNode, error) {
+	p := &tomlParser{
+		s:           data,
+		lineStarts:  []int{0},
+		root:        newTreeObject(1, 1),
+		explicit:    make(map[*treeNode]bool),
+		dotted:      make(map[*treeNode]bool),
+		frozen:      make(map[*treeNode]bool),
+		tableArrays: make(map[*treeNode]bool),
+	}
+	for i := 0; i < len(data); i++ {
+		if data[i] == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+	p.current = p.root
+
+	for {
+		p.skipBlank()
+		if p.i >= len(p.s) {
+			return p.root, nil
+		}
+		var err error
+		if p.s[p.i] == '[' {
+			err = p.parseTableHeader()
+		} else {
+			err = p.parseKeyValue(p.current)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectLineEnd(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *tomlParser) position(offset int) (line, column int) {
+	line = sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > offset })
+	return line, offset - p.lineStarts[line-1] + 1
+}
+
+func (p *tomlParser) errorAt(offset int, format string, args ...interface{}) error {
+	line, column := p.position(offset)
+	return &treeSyntaxError{line: line, column: column, msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *tomlParser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.i, format, args...)
+}
+
+func (p *tomlParser) newNode(kind treeNodeKind, offset int) *treeNode {
+	line, column := p.position(offset)
+	node := &treeNode{kind: kind, line: line, column: column}
+	if kind == treeObject {
+		node.props = make(map[string]*treeNode)
+	}
+	return node
+}
+
+func (p *tomlParser) skipSpaces() {
+	for p.i < len(p.s) && (p.s[p.i] == ' ' || p.s[p.i] == '\t') {
+		p.i++
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	if p.i < len(p.s) && p.s[p.i] == '#' {
+		for p.i < len(p.s) && p.s[p.i] != '\n' {
+			p.i++
+		}
+	}
+}
+
+// skipBlank skips all whitespace, new lines and comments.
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpaces()
+		p.skipComment()
+		switch {
+		case strings.HasPrefix(p.s[p.i:], "\n"):
+			p.i++
+		case strings.HasPrefix(p.s[p.i:], "\r\n"):
+			p.i += 2
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) expectLineEnd() error {
+	p.skipSpaces()
+	p.skipComment()
+	if p.i < len(p.s) && !strings.HasPrefix(p.s[p.i:], "\n") && !strings.HasPrefix(p.s[p.i:], "\r\n") {
+		return p.errorf("expected a new line, got %q", p.s[p.i])
+	}
+	return nil
+}
+
+func (p *tomlParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.i:], prefix) {
+		p.i += len(prefix)
+		return true
+	}
+	return false
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+// parseKey parses simple and dotted keys, e.g. `a`, `"a b"` and `a."b".c`.
+func (p *tomlParser) parseKey() ([]string, error) {
+	var keys []string
+	for {
+		p.skipSpaces()
+		var key string
+		switch {
+		case p.i >= len(p.s):
+			return nil, p.errorf("expected a key")
+		case p.s[p.i] == '"' || p.s[p.i] == '\'':
+			var err error
+			if key, err = p.parseSingleLineString(); err != nil {
+				return nil, err
+			}
+		default:
+			start := p.i
+			for p.i < len(p.s) && isTOMLBareKeyChar(p.s[p.i]) {
+				p.i++
+			}
+			if start == p.i {
+				return nil, p.errorf("expected a key, got %q", p.s[p.i])
+			}
+			key = p.s[start:p.i]
+		}
+		keys = append(keys, key)
+
+		p.skipSpaces()
+		if !p.consume(".") {
+			return keys, nil
+		}
+	}
+}
+
+func (p *tomlParser) parseKeyValue(table *treeNode) error {
+	keyStart := p.i
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if !p.consume("=") {
+		return p.errorf("expected '=' after the key %s", strings.Join(keys, "."))
+	}
+	p.skipSpaces()
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys[:len(keys)-1] {
+		next, exists := table.props[key]
+		switch {
+		case !exists:
+			next = p.newNode(treeObject, keyStart)
+			p.dotted[next] = true
+			table.set(key, next)
+		case next.kind != treeObject || p.frozen[next] || p.explicit[next] || !p.dotted[next]:
+			return p.errorAt(keyStart, "key %s is already defined", strings.Join(keys[:i+1], "."))
+		}
+		table = next
+	}
+
+	lastKey := keys[len(keys)-1]
+	if _, exists := table.props[lastKey]; exists {
+		return p.errorAt(keyStart, "key %s is already defined", strings.Join(keys, "."))
+	}
+	table.set(lastKey, value)
+	return nil
+}
+
+func (p *tomlParser) parseTableHeader() error {
+	start := p.i
+	isArray := p.consume("[[")
+	if !isArray {
+		p.i++ // skip '['
+	}
+	keys, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if (isArray && !p.consume("]]")) || (!isArray && !p.consume("]")) {
+		return p.errorf("expected the end of the table header")
+	}
+	name := strings.Join(keys, ".")
+
+	table := p.root
+	for i, key := range keys[:len(keys)-1] {
+		next, exists := table.props[key]
+		if !exists {
+			next = p.newNode(treeObject, start)
+			table.set(key, next)
+		}
+		if p.tableArrays[next] {
+			next = next.elements[len(next.elements)-1]
+		}
+		if next.kind != treeObject || p.frozen[next] {
+			return p.errorAt(start, "key %s is already defined", strings.Join(keys[:i+1], "."))
+		}
+		table = next
+	}
+
+	lastKey := keys[len(keys)-1]
+	existing, exists := table.props[lastKey]
+	if isArray {
+		if !exists {
+			existing = p.newNode(treeArray, start)
+			p.tableArrays[existing] = true
+			table.set(lastKey, existing)
+		} else if !p.tableArrays[existing] {
+			return p.errorAt(start, "key %s is already defined and is not an array of tables", name)
+		}
+		p.current = p.newNode(treeObject, start)
+		existing.elements = append(existing.elements, p.current)
+		return nil
+	}
+
+	if exists {
+		if existing.kind != treeObject || p.explicit[existing] || p.dotted[existing] || p.frozen[existing] {
+			return p.errorAt(start, "table %s is already defined", name)
+		}
+		// the table was implicitly created by a previous header like [a.b.c]
+		existing.line, existing.column = p.position(start)
+	} else {
+		existing = p.newNode(treeObject, start)
+		table.set(lastKey, existing)
+	}
+	p.explicit[existing] = true
+	p.current = existing
+	return nil
+}
+
+func (p *tomlParser) parseValue() (*treeNode, error) {
+	if p.i >= len(p.s) {
+		return nil, p.errorf("expected a value")
+	}
+	start := p.i
+
+	switch c := p.s[p.i]; {
+	case c == '"' || c == '\'':
+		var val string
+		var err error
+		if strings.HasPrefix(p.s[p.i:], `"""`) || strings.HasPrefix(p.s[p.i:], `'''`) {
+			val, err = p.parseMultiLineString()
+		} else {
+			val, err = p.parseSingleLineString()
+		}
+		if err != nil {
+			return nil, err
+		}
+		node := p.newNode(treeScalar, start)
+		node.value, node.raw = val, val
+		return node, nil
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseInlineTable()
+	}
+
+	// All other values are bare tokens, i.e. booleans, numbers and datetimes.
+	for p.i < len(p.s) && (isTOMLBareKeyChar(p.s[p.i]) || strings.IndexByte(":.+", p.s[p.i]) >= 0) {
+		p.i++
+	}
+	// A space can be used instead of T in datetimes, e.g. 1979-05-27 07:32:00
+	if tomlDateRegexp.MatchString(p.s[start:p.i]) && p.i+3 < len(p.s) && p.s[p.i] == ' ' &&
+		isDigit(p.s[p.i+1]) && isDigit(p.s[p.i+2]) && p.s[p.i+3] == ':' {
+		p.i++
+		for p.i < len(p.s) && (isTOMLBareKeyChar(p.s[p.i]) || strings.IndexByte(":.+", p.s[p.i]) >= 0) {
+			p.i++
+		}
+	}
+	token := p.s[start:p.i]
+	if token == "" {
+		return nil, p.errorf("expected a value, got %q", p.s[p.i])
+	}
+
+	value, err := parseTOMLBareValue(token)
+	if err != nil {
+		return nil, p.errorAt(start, "%s", err)
+	}
+	node := p.newNode(treeScalar, start)
+	node.value, node.raw = value, token
+	switch val := value.(type) {
+	case int64:
+		node.raw = strconv.FormatInt(val, 10)
+	case float64:
+		node.raw = strconv.FormatFloat(val, 'g', -1, 64)
+	}
+	return node, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+var (
+	tomlDecimalRegexp = regexp.MustCompile(`^[+-]?(0|[1-9](_?[0-9])*)$`)
+	tomlPrefixRegexp  = regexp.MustCompile(`^0(x[0-9A-Fa-f](_?[0-9A-Fa-f])*|o[0-7](_?[0-7])*|b[01](_?[01])*)$`)
+	tomlFloatRegexp   = regexp.MustCompile(
+		`^[+-]?(0|[1-9](_?[0-9])*)((\.[0-9](_?[0-9])*)([eE][+-]?[0-9](_?[0-9])*)?|[eE][+-]?[0-9](_?[0-9])*)$`,
+	)
+	tomlDateRegexp     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	tomlDateTimeRegexp = regexp.MustCompile(
+		`^(\d{4}-\d{2}-\d{2})[Tt ](\d{2}:\d{2}:\d{2}(\.\d+)?)([Zz]|[+-]\d{2}:\d{2})?$`,
+	)
+	tomlTimeRegexp = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}(\.\d+)?$`)
+)
+
+// parseTOMLBareValue parses booleans, numbers and datetimes. Offset datetimes
+// are returned as time.Time values in their original offset, while local ones
+// are in the time.Local location.
+func parseTOMLBareValue(token string) (interface{}, error) {
+	switch token {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+
+	digits := strings.ReplaceAll(token, "_", "")
+	switch {
+	case tomlDecimalRegexp.MatchString(token):
+		val, err := strconv.ParseInt(digits, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("integer %s is out of range", token)
+		}
+		return val, nil
+	case tomlPrefixRegexp.MatchString(token):
+		base := map[byte]int{'x': 16, 'o': 8, 'b': 2}[token[1]]
+		val, err := strconv.ParseInt(digits[2:], base, 64)
+		if err != nil {
+			return nil, fmt.Errorf("integer %s is out of range", token)
+		}
+		return val, nil
+	case tomlFloatRegexp.MatchString(token):
+		val, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return nil, fmt.Errorf("float %s is out of range", token)
+		}
+		return val, nil
+	}
+
+	var val time.Time
+	var err error
+	if m := tomlDateTimeRegexp.FindStringSubmatch(token); m != nil {
+		normalized := m[1] + "T" + m[2] + strings.ToUpper(m[4])
+		if m[4] == "" {
+			val, err = time.ParseInLocation("2006-01-02T15:04:05.999999999", normalized, time.Local)
+		} else {
+			val, err = time.Parse(time.RFC3339Nano, normalized)
+		}
+	} else if tomlDateRegexp.MatchString(token) {
+		val, err = time.ParseInLocation("2006-01-02", token, time.Local)
+	} else if tomlTimeRegexp.MatchString(token) {
+		val, err = time.ParseInLocation("15:04:05.999999999", token, time.Local)
+	} else {
+		return nil, fmt.Errorf("invalid value %q", token)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid datetime %q", token)
+	}
+	return val, nil
+}
+
+func (p *tomlParser) parseArray() (*treeNode, error) {
+	node := p.newNode(treeArray, p.i)
+	p.i++ // skip '['
+	for {
+		p.skipBlank()
+		if p.consume("]") {
+			return node, nil
+		}
+		element, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		node.elements = append(node.elements, element)
+
+		p.skipBlank()
+		if p.consume("]") {
+			return node, nil
+		}
+		if !p.consume(",") {
+			if p.i >= len(p.s) {
+				return nil, p.errorAt(p.i, "unterminated array")
+			}
+			return nil, p.errorf("expected ',' or ']' in the array, got %q", p.s[p.i])
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (*treeNode, error) {
+	node := p.newNode(treeObject, p.i)
+	p.i++ // skip '{'
+	p.skipSpaces()
+	if p.consume("}") {
+		p.frozen[node] = true
+		return node, nil
+	}
+	for {
+		if err := p.parseKeyValue(node); err != nil {
+			return nil, err
+		}
+		p.skipSpaces()
+		if p.consume("}") {
+			p.frozen[node] = true
+			return node, nil
+		}
+		if !p.consume(",") {
+			if p.i >= len(p.s) {
+				return nil, p.errorAt(p.i, "unterminated inline table")
+			}
+			return nil, p.errorf("expected ',' or '}' in the inline table, got %q", p.s[p.i])
+		}
+		p.skipSpaces()
+	}
+}
+
+func (p *tomlParser) parseSingleLineString() (string, error) {
+	quote := p.s[p.i]
+	start := p.i
+	p.i++
+	var sb strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		switch {
+		case c == quote:
+			p.i++
+			return sb.String(), nil
+		case c == '\n' || c == '\r':
+			return "", p.errorAt(start, "unterminated string")
+		case c == '\\' && quote == '"':
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		case c < 0x20 && c != '\t' || c == 0x7f:
+			return "", p.errorf("control characters are not allowed in strings")
+		default:
+			sb.WriteByte(c)
+			p.i++
+		}
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+func (p *tomlParser) parseMultiLineString() (string, error) {
+	quote := p.s[p.i]
+	delimiter := strings.Repeat(string(quote), 3)
+	start := p.i
+	p.i += 3
+	// a new line immediately after the opening delimiter is trimmed
+	if !p.consume("\n") {
+		p.consume("\r\n")
+	}
+
+	var sb strings.Builder
+	for p.i < len(p.s) {
+		if strings.HasPrefix(p.s[p.i:], delimiter) {
+			p.i += 3
+			// up to 2 quotes are allowed right before the closing delimiter
+			for extra := 0; extra < 2 && p.i < len(p.s) && p.s[p.i] == quote; extra++ {
+				sb.WriteByte(quote)
+				p.i++
+			}
+			return sb.String(), nil
+		}
+
+		c := p.s[p.i]
+		switch {
+		case c == '\\' && quote == '"':
+			// a "line ending backslash" trims all whitespace up to the next
+			// non-whitespace character, including new lines
+			rest := strings.TrimLeft(p.s[p.i+1:], " \t")
+			if strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n") {
+				trimmed := strings.TrimLeft(rest, " \t\r\n")
+				p.i = len(p.s) - len(trimmed)
+				continue
+			}
+			if err := p.parseEscape(&sb); err != nil {
+				return "", err
+			}
+		case c < 0x20 && c != '\t' && c != '\n' && c != '\r' || c == 0x7f:
+			return "", p.errorf("control characters are not allowed in strings")
+		default:
+			sb.WriteByte(c)
+			p.i++
+		}
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+func (p *tomlParser) parseEscape(sb *strings.Builder) error {
+	start := p.i
+	p.i++ // skip the backslash
+	if p.i >= len(p.s) {
+		return p.errorAt(start, "unterminated escape sequence")
+	}
+
+	simple := map[byte]byte{'b': '\b', 't': '\t', 'n': '\n', 'f': '\f', 'r': '\r', 'e': '\x1b', '"': '"', '\\': '\\'}
+	if c, ok := simple[p.s[p.i]]; ok {
+		sb.WriteByte(c)
+		p.i++
+		return nil
+	}
+
+	digits := map[byte]int{'u': 4, 'U': 8}[p.s[p.i]]
+	if digits == 0 || p.i+digits >= len(p.s) {
+		return p.errorAt(start, "invalid escape sequence '\\%c'", p.s[p.i])
+	}
+	code, err := strconv.ParseUint(p.s[p.i+1:p.i+1+digits], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return p.errorAt(start, "invalid escape sequence '\\%s'", p.s[p.i:p.i+1+digits])
+	}
+	sb.WriteRune(rune(code))
+	p.i += digits + 1
+	return nil
+}
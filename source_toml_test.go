@@ -0,0 +1,244 @@
+package croconf
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testTOMLConfig = `
+# comments should be ignored
+vus = 10
+pi = 3.14
+throw = true
+userAgent = "croconf \"test\"" # a trailing comment
+hosts = [
+  "example.com",
+  'k6.io', # trailing commas are allowed
+]
+maxRedirects = 0x0A
+bigNumber = 1_000_000
+startTime = 1979-05-27T07:32:00-08:00
+localDate = 1979-05-27
+
+[dns]
+server = "8.8.8.8"
+ttl = "5m"
+
+[tags]
+foo = "bar"
+"baz qux" = 'literal \n'
+
+[[targets]]
+url = "https://k6.io"
+maxRetries = 3
+tags = ["a", "b"]
+
+[[targets]]
+url = "https://example.com"
+
+[scenarios.default]
+exec = """
+first line
+second line"""
+`
+
+func TestTOMLBindSingleValues(t *testing.T) {
+	t.Parallel()
+
+	source := NewTOMLSource([]byte(testTOMLConfig))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var vus, maxRedirects, bigNumber int64
+	var pi float64
+	var throw bool
+	var userAgent, exec, server string
+	var ttl time.Duration
+	var startTime time.Time
+	var localDate string
+
+	bindings := []Binding{
+		source.From("vus").BindIntValueTo(&vus),
+		source.From("maxRedirects").BindIntValueTo(&maxRedirects),
+		source.From("bigNumber").BindIntValueTo(&bigNumber),
+		source.From("pi").BindFloatValueTo(&pi),
+		source.From("throw").BindBoolValueTo(&throw),
+		source.From("userAgent").BindStringValueTo(&userAgent),
+		source.From("scenarios").From("default").From("exec").BindStringValueTo(&exec),
+		source.From("dns").From("server").BindStringValueTo(&server),
+		source.From("dns").From("ttl").BindDurationValueTo(&ttl, time.Second),
+		source.From("startTime").BindTextBasedValueTo(&startTime),
+		source.From("localDate").BindStringValueTo(&localDate),
+	}
+	for _, b := range bindings {
+		if err := b.Apply(); err != nil {
+			t.Fatalf("unexpected error for %s: %s", b.(BindingFromSource).BoundName(), err)
+		}
+	}
+
+	if vus != 10 || maxRedirects != 10 || bigNumber != 1000000 || pi != 3.14 || !throw {
+		t.Errorf("unexpected values %d %d %d %g %t", vus, maxRedirects, bigNumber, pi, throw)
+	}
+	if userAgent != `croconf "test"` || exec != "first line\nsecond line" || server != "8.8.8.8" {
+		t.Errorf("unexpected string values %q, %q and %q", userAgent, exec, server)
+	}
+	if ttl != 5*time.Minute || localDate != "1979-05-27" {
+		t.Errorf("unexpected ttl %s or local date %s", ttl, localDate)
+	}
+	if expTime := time.Date(1979, 5, 27, 15, 32, 0, 0, time.UTC); !startTime.Equal(expTime) {
+		t.Errorf("expected start time %s, got %s", expTime, startTime)
+	}
+
+	binding := source.From("dns").From("server").BindIntValueTo(&vus)
+	if bfs := binding.(BindingFromSource); bfs.BoundName() != "dns.server" || bfs.Source() != source {
+		t.Errorf("unexpected bound name %s or source", bfs.BoundName())
+	}
+	expErr := `toml:17:10: dns.server: expected an integer, got "8.8.8.8"`
+	if err := binding.Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	var val uint64
+	expErr = `toml:22:13: tags.baz qux: expected an unsigned integer, got "literal \\n"`
+	if err := source.From("tags").From("baz qux").BindUintValueTo(&val).Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	expErr = "field missing is missing in config source toml"
+	if err := source.From("missing").BindIntValueTo(&vus).Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
+
+func TestTOMLBindCollections(t *testing.T) {
+	t.Parallel()
+
+	source := NewTOMLSource([]byte(testTOMLConfig), WithTOMLFileName("config.toml"))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var hosts []string
+	var tags map[string]string
+	var targets []testTarget
+
+	cm := NewManager()
+	cm.AddField(NewStringSliceField(&hosts, source.From("hosts")))
+	cm.AddField(NewStringMapField(&tags, source.From("tags")))
+	cm.AddField(NewStructSliceField(&targets, func(el *testTarget, binder ObjectValueBinder, cm *Manager) {
+		cm.AddField(NewStringField(&el.URL, binder.Property("url")))
+		cm.AddField(NewInt64Field(&el.MaxRetries, binder.Property("maxRetries")))
+		cm.AddField(NewStringSliceField(&el.Tags, binder.Property("tags")))
+	}, source.From("targets")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+
+	if exp := []string{"example.com", "k6.io"}; !reflect.DeepEqual(hosts, exp) {
+		t.Errorf("expected hosts %v, got %v", exp, hosts)
+	}
+	if exp := map[string]string{"foo": "bar", "baz qux": `literal \n`}; !reflect.DeepEqual(tags, exp) {
+		t.Errorf("expected tags %v, got %v", exp, tags)
+	}
+	expTargets := []testTarget{
+		{URL: "https://k6.io", MaxRetries: 3, Tags: []string{"a", "b"}},
+		{URL: "https://example.com"},
+	}
+	if !reflect.DeepEqual(targets, expTargets) {
+		t.Errorf("expected targets %v, got %v", expTargets, targets)
+	}
+
+	var vus []int64
+	expErr := `config.toml:3:7: vus: expected an array, got 10`
+	if err := NewInt64SliceField(&vus, source.From("vus")).Bindings()[0].Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
+
+func TestTOMLParseErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		toml   string
+		expErr string
+	}{
+		{"a = 1\na = 2", "toml:2:1: key a is already defined"},
+		{"a = 1 b = 2", "toml:1:7: expected a new line, got 'b'"},
+		{"a = [1, 2", "toml:1:10: unterminated array"},
+		{"a = \"foo\nb = 1", "toml:1:5: unterminated string"},
+		{"a = 9223372036854775808", "toml:1:5: integer 9223372036854775808 is out of range"},
+		{"a = 01", `toml:1:5: invalid value "01"`},
+		{"a = {b = 1}\n[a]", "toml:2:1: table a is already defined"},
+		{"[a]\nb = 1\n[a]", "toml:3:1: table a is already defined"},
+		{"a = [1]\n[[a]]", "toml:2:1: key a is already defined and is not an array of tables"},
+		{"a = {b = 1}\na.c = 2", "toml:2:1: key a is already defined"},
+		{"a.b = 1\n[a]", "toml:2:1: table a is already defined"},
+		{"a = 1979-13-27", `toml:1:5: invalid datetime "1979-13-27"`},
+		{"a = \"\\x\"", `toml:1:6: invalid escape sequence '\x'`},
+		{"a", "toml:1:2: expected '=' after the key a"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.toml, func(t *testing.T) {
+			t.Parallel()
+			err := NewTOMLSource([]byte(tc.toml)).Initialize()
+			if err == nil || err.Error() != tc.expErr {
+				t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestTOMLParseValues(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		toml string
+		exp  interface{}
+	}{
+		{"v = 0o17", int64(15)},
+		{"v = 0b101", int64(5)},
+		{"v = -12", int64(-12)},
+		{"v = 6.626e-34", 6.626e-34},
+		{"v = 1e3", float64(1000)},
+		{"v = false", false},
+		{"v = '''\nit's\nraw\\n'''", "it's\nraw\\n"},
+		{"v = \"\"\"a \\\n    b\"\"\"", "a b"},
+		{"v = \"\\u00e9\"", "é"},
+		{"v = 1979-05-27 07:32:00Z", time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)},
+		{"v.a.b = 1\nv.a.c = 2", map[string]interface{}{"a": map[string]interface{}{"b": int64(1), "c": int64(2)}}},
+		{"v = [[1, 2], ['a']]", []interface{}{[]interface{}{int64(1), int64(2)}, []interface{}{"a"}}},
+		{"v = {a.b = 1, c = [true]}", map[string]interface{}{
+			"a": map[string]interface{}{"b": int64(1)}, "c": []interface{}{true},
+		}},
+		{"[v.a]\nb = 1\n[v]\nc = 2", map[string]interface{}{"a": map[string]interface{}{"b": int64(1)}, "c": int64(2)}},
+		{"[[v]]\na = 1\n[v.b]\nc = 2\n[[v]]", []interface{}{
+			map[string]interface{}{"a": int64(1), "b": map[string]interface{}{"c": int64(2)}},
+			map[string]interface{}{},
+		}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.toml, func(t *testing.T) {
+			t.Parallel()
+			root, err := parseTOML(tc.toml)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			val := treeNodeToInterface(root.props["v"])
+			if expTime, ok := tc.exp.(time.Time); ok {
+				if valTime, ok := val.(time.Time); !ok || !valTime.Equal(expTime) {
+					t.Errorf("expected %s, got %#v", expTime, val)
+				}
+				return
+			}
+			if !reflect.DeepEqual(val, tc.exp) {
+				t.Errorf("expected %#v, got %#v", tc.exp, val)
+			}
+		})
+	}
+}
@@ -0,0 +1,593 @@
+package croconf
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// treeNode is a generic parsed document value. It is used internally by the
+// sources for the structured config formats (e.g. YAML) that don't have a
+// convenient parser in the stdlib, so they can share the same binders.
+type treeNode struct {
+	kind treeNodeKind
+
+	// value is one of string, int64, uint64, float64, bool or time.Time for
+	// scalars, while raw is their original text representation.
+	value interface{}
+	raw   string
+
+	elements []*treeNode          // for arrays
+	keys     []string             // for objects, in the original order
+	props    map[string]*treeNode // for objects
+
+	// scalar is the value that scalar binders use for arrays and objects that
+	// also have a single value, e.g. for formats like INI, where keys can be
+	// repeated or be the prefix of other keys
+	scalar *treeNode
+
+	line, column int // 1-based, 0 if unknown
+
+	// origin is the source the value came from, for documents that are merged
+	// from multiple files. If it's nil, the value is from the document source.
+	origin Source
+
+	// used is set when the value is successfully bound, or when it's an
+	// object that nested values were looked up in, and requested has all
+	// property names that were looked up in an object, even missing ones, so
+	// they can be suggested as alternatives to any unknown keys
+	used      bool
+	requested map[string]bool
+}
+
+type treeNodeKind int
+
+const (
+	treeNull treeNodeKind = iota
+	treeScalar
+	treeArray
+	treeObject
+)
+
+func newTreeObject(line, column int) *treeNode {
+	return &treeNode{kind: treeObject, props: make(map[string]*treeNode), line: line, column: column}
+}
+
+func (tn *treeNode) set(key string, value *treeNode) {
+	if _, ok := tn.props[key]; !ok {
+		tn.keys = append(tn.keys, key)
+	}
+	tn.props[key] = value
+}
+
+// resetUsed clears the used marks of the node and all of its nested values,
+// for documents that aren't parsed again when the source is initialized.
+func (tn *treeNode) resetUsed() {
+	tn.used, tn.requested = false, nil
+	for _, el := range tn.elements {
+		el.resetUsed()
+	}
+	for _, prop := range tn.props {
+		prop.resetUsed()
+	}
+	if tn.scalar != nil {
+		tn.scalar.resetUsed()
+	}
+}
+
+// describe returns a short human-readable description of the node value, to be
+// used in error messages.
+func (tn *treeNode) describe() string {
+	switch tn.kind {
+	case treeNull:
+		return "null"
+	case treeArray:
+		return "an array"
+	case treeObject:
+		return "an object"
+	}
+	if _, ok := tn.value.(string); ok {
+		return strconv.Quote(tn.value.(string))
+	}
+	return tn.raw
+}
+
+// treeDocument is the part of a tree-based source that its binders need.
+type treeDocument struct {
+	source   Source
+	fileName string
+	root     *treeNode
+
+	// splitLists allows string values like "a, b, c" to be bound to arrays,
+	// for formats that don't have native arrays.
+	splitLists bool
+}
+
+func (td *treeDocument) name() string {
+	if td.fileName != "" {
+		return td.fileName
+	}
+	return td.source.GetName()
+}
+
+func (td *treeDocument) location(node *treeNode) string {
+	if node.origin != nil {
+		return formatLocation(node.origin.GetName(), node.line, node.column)
+	}
+	return formatLocation(td.name(), node.line, node.column)
+}
+
+// init parses the document and converts any syntax errors to SourceParseError.
+func (td *treeDocument) init(parse func() (*treeNode, error)) error {
+	root, err := parse()
+	if err != nil {
+		var syntaxErr *treeSyntaxError
+		if errors.As(err, &syntaxErr) {
+			return NewSourceParseError(td.name(), syntaxErr.line, syntaxErr.column, syntaxErr)
+		}
+		return err
+	}
+	td.root = root
+	return nil
+}
+
+type treeSyntaxError struct {
+	line, column int
+	msg          string
+}
+
+func (e *treeSyntaxError) Error() string {
+	return e.msg
+}
+
+func (td *treeDocument) from(name string) *treeBinder {
+	return &treeBinder{
+		doc:  td,
+		name: name,
+		lookup: func() (*treeNode, error) {
+			return td.lookupProperty(td.root, name, name)
+		},
+	}
+}
+
+func (td *treeDocument) fromPath(path []string) *treeBinder {
+	binder := td.from(path[0])
+	for _, part := range path[1:] {
+		binder = binder.From(part)
+	}
+	return binder
+}
+
+func (td *treeDocument) lookupProperty(parent *treeNode, fullName, property string) (*treeNode, error) {
+	if parent == nil {
+		return nil, NewBindFieldMissingError(td.source.GetName(), fullName)
+	}
+	if parent.kind != treeObject {
+		return nil, NewSourceValueError(
+			td.location(parent), strings.TrimSuffix(fullName, "."+property),
+			fmt.Errorf("expected an object, got %s", parent.describe()),
+		)
+	}
+	parent.used = true
+	if parent.requested == nil {
+		parent.requested = make(map[string]bool)
+	}
+	parent.requested[property] = true
+	node, ok := parent.props[property]
+	if !ok || node.kind == treeNull {
+		// Explicit nulls are treated the same as missing values, so that users
+		// can have empty placeholders in their config files.
+		return nil, NewBindFieldMissingError(td.source.GetName(), fullName)
+	}
+	return node, nil
+}
+
+// UnknownValues returns errors for all keys in the document, including nested
+// ones, that weren't looked up by any binder. They can only be detected after
+// all fields have been consolidated.
+func (td *treeDocument) UnknownValues() []error {
+	var errs []error
+	if td.root != nil {
+		td.root.used = true
+		td.collectUnknownKeys(td.root, "", &errs)
+	}
+	return errs
+}
+
+func (td *treeDocument) collectUnknownKeys(node *treeNode, path string, errs *[]error) {
+	if !node.used {
+		return
+	}
+	prefix := path
+	if prefix != "" {
+		prefix += "."
+	}
+
+	for _, key := range node.keys {
+		prop := node.props[key]
+		if prop.used {
+			td.collectUnknownKeys(prop, prefix+key, errs)
+			continue
+		}
+		known := make([]string, 0, len(node.requested))
+		for name := range node.requested {
+			known = append(known, name)
+		}
+		sort.Strings(known)
+		suggestion := closestName(key, known)
+		if suggestion != "" {
+			suggestion = prefix + suggestion
+		}
+		*errs = append(*errs, NewUnknownValueError(td.location(prop), "key", prefix+key, suggestion))
+	}
+	for i, el := range node.elements {
+		td.collectUnknownKeys(el, fmt.Sprintf("%s[%d]", path, i), errs)
+	}
+}
+
+// treeBinder implements all of the binder interfaces for a single value in a
+// treeDocument. It's lazy, the value is looked up only when bindings are
+// applied.
+type treeBinder struct {
+	doc    *treeDocument
+	name   string
+	lookup func() (*treeNode, error)
+}
+
+func (tb *treeBinder) newBinding(apply func() error) *treeBinding {
+	binding := &treeBinding{binder: tb}
+	binding.apply = func() error {
+		if err := apply(); err != nil {
+			return err
+		}
+		// Remember where the value came from, for the Source() of the binding
+		if node, err := tb.lookup(); err == nil {
+			binding.origin = node.origin
+		}
+		return nil
+	}
+	return binding
+}
+
+func (tb *treeBinder) From(name string) *treeBinder {
+	fullName := tb.name + "." + name
+	return &treeBinder{
+		doc:  tb.doc,
+		name: fullName,
+		lookup: func() (*treeNode, error) {
+			parent, err := tb.lookup()
+			if err != nil {
+				return nil, err
+			}
+			return tb.doc.lookupProperty(parent, fullName, name)
+		},
+	}
+}
+
+func (tb *treeBinder) propertyPrefix() string {
+	return tb.name + "."
+}
+
+func (tb *treeBinder) Property(name string) PropertyValueBinder {
+	return tb.From(name)
+}
+
+func (tb *treeBinder) valueError(node *treeNode, format string, args ...interface{}) error {
+	return NewSourceValueError(tb.doc.location(node), tb.name, fmt.Errorf(format, args...))
+}
+
+// bindScalar looks up the value and calls bind with it if it's a scalar.
+func (tb *treeBinder) bindScalar(expected string, bind func(node *treeNode) error) func() error {
+	return func() error {
+		node, err := tb.lookup()
+		if err != nil {
+			return err
+		}
+		looked := node
+		for node.kind != treeScalar && node.scalar != nil {
+			node = node.scalar
+		}
+		if node.kind != treeScalar {
+			return tb.valueError(node, "expected %s, got %s", expected, node.describe())
+		}
+		if err := bind(node); err != nil {
+			var bindErr *BindValueError
+			if errors.As(err, &bindErr) && errors.Is(bindErr.Err, strconv.ErrRange) {
+				err = fmt.Errorf("value %s is out of range", node.describe())
+			} else if errors.As(err, &bindErr) {
+				err = fmt.Errorf("expected %s, got %s", expected, node.describe())
+			}
+			return NewSourceValueError(tb.doc.location(node), tb.name, err)
+		}
+		for ; looked != nil; looked = looked.scalar {
+			looked.used = true
+		}
+		return nil
+	}
+}
+
+func (tb *treeBinder) BindStringValueTo(dest *string) Binding {
+	return tb.newBinding(tb.bindScalar("a string", func(node *treeNode) error {
+		if s, ok := node.value.(string); ok {
+			*dest = s
+		} else {
+			*dest = node.raw
+		}
+		return nil
+	}))
+}
+
+func (tb *treeBinder) BindTextBasedValueTo(dest encoding.TextUnmarshaler) Binding {
+	return tb.newBinding(tb.bindScalar("a string", func(node *treeNode) error {
+		switch val := node.value.(type) {
+		case string:
+			return dest.UnmarshalText([]byte(val))
+		case time.Time:
+			if t, ok := dest.(*time.Time); ok {
+				*t = val
+				return nil
+			}
+		}
+		return dest.UnmarshalText([]byte(node.raw))
+	}))
+}
+
+func (tb *treeBinder) BindIntValueTo(dest *int64) Binding {
+	return tb.newBinding(tb.bindScalar("an integer", func(node *treeNode) error {
+		switch val := node.value.(type) {
+		case int64:
+			*dest = val
+		case uint64:
+			if val > math.MaxInt64 {
+				return fmt.Errorf("value %d is out of range", val)
+			}
+			*dest = int64(val)
+		case float64:
+			if val != math.Trunc(val) || val < math.MinInt64 || val >= math.MaxInt64 {
+				return fmt.Errorf("expected an integer, got %s", node.raw)
+			}
+			*dest = int64(val)
+		case string:
+			intVal, bindErr := parseInt(val)
+			if bindErr != nil {
+				return bindErr
+			}
+			*dest = intVal
+		default:
+			return fmt.Errorf("expected an integer, got %s", node.describe())
+		}
+		return nil
+	}))
+}
+
+func (tb *treeBinder) BindUintValueTo(dest *uint64) Binding {
+	return tb.newBinding(tb.bindScalar("an unsigned integer", func(node *treeNode) error {
+		switch val := node.value.(type) {
+		case int64:
+			if val < 0 {
+				return fmt.Errorf("value %d is out of range", val)
+			}
+			*dest = uint64(val)
+		case uint64:
+			*dest = val
+		case float64:
+			if val != math.Trunc(val) || val < 0 || val >= math.MaxUint64 {
+				return fmt.Errorf("expected an unsigned integer, got %s", node.raw)
+			}
+			*dest = uint64(val)
+		case string:
+			uintVal, bindErr := parseUint(val)
+			if bindErr != nil {
+				return bindErr
+			}
+			*dest = uintVal
+		default:
+			return fmt.Errorf("expected an unsigned integer, got %s", node.describe())
+		}
+		return nil
+	}))
+}
+
+func (tb *treeBinder) BindFloatValueTo(dest *float64) Binding {
+	return tb.newBinding(tb.bindScalar("a number", func(node *treeNode) error {
+		switch val := node.value.(type) {
+		case int64:
+			*dest = float64(val)
+		case uint64:
+			*dest = float64(val)
+		case float64:
+			*dest = val
+		case string:
+			floatVal, bindErr := parseFloat(val)
+			if bindErr != nil {
+				return bindErr
+			}
+			*dest = floatVal
+		default:
+			return fmt.Errorf("expected a number, got %s", node.describe())
+		}
+		return nil
+	}))
+}
+
+func (tb *treeBinder) BindBoolValueTo(dest *bool) Binding {
+	return tb.newBinding(tb.bindScalar("a boolean", func(node *treeNode) error {
+		switch val := node.value.(type) {
+		case bool:
+			*dest = val
+		case string:
+			boolVal, err := strconv.ParseBool(val)
+			if err != nil {
+				return NewBindValueError("BindBoolValue", val, err)
+			}
+			*dest = boolVal
+		default:
+			return fmt.Errorf("expected a boolean, got %s", node.describe())
+		}
+		return nil
+	}))
+}
+
+// BindDurationValueTo accepts both strings, e.g. "1m30s" or "2d", and numbers,
+// which are multiplied by bareNumberUnit.
+func (tb *treeBinder) BindDurationValueTo(dest *time.Duration, bareNumberUnit time.Duration) Binding {
+	return tb.newBinding(tb.bindScalar("a duration", func(node *treeNode) error {
+		var strVal string
+		switch val := node.value.(type) {
+		case string:
+			strVal = val
+		case int64, uint64, float64:
+			strVal = node.raw
+		default:
+			return fmt.Errorf("expected a string or a number, got %s", node.describe())
+		}
+		val, bindErr := parseDuration(strVal, bareNumberUnit)
+		if bindErr != nil {
+			return bindErr
+		}
+		*dest = val
+		return nil
+	}))
+}
+
+func (tb *treeBinder) arrayElements() (int, func(int) *treeBinder, error) {
+	node, err := tb.lookup()
+	if err != nil {
+		return 0, nil, err
+	}
+	looked := node
+	if node.kind == treeObject && node.scalar != nil {
+		node = node.scalar
+	}
+	if str, ok := node.value.(string); ok && tb.doc.splitLists {
+		node.used = true
+		node = splitListNode(node, str)
+	}
+	if node.kind != treeArray {
+		return 0, nil, tb.valueError(node, "expected an array, got %s", node.describe())
+	}
+	// The elements are marked as used when they are bound themselves
+	looked.used, node.used = true, true
+
+	return len(node.elements), func(elNum int) *treeBinder {
+		name := fmt.Sprintf("%s[%d]", tb.name, elNum)
+		return &treeBinder{
+			doc:  tb.doc,
+			name: name,
+			lookup: func() (*treeNode, error) {
+				if elNum >= len(node.elements) {
+					return nil, fmt.Errorf("tried to access invalid element %s, array only has %d elements", name, len(node.elements))
+				}
+				el := node.elements[elNum]
+				if el.kind == treeNull {
+					return nil, tb.valueError(el, "unexpected null element %s", name)
+				}
+				return el, nil
+			},
+		}
+	}, nil
+}
+
+// splitListNode converts a string with comma-separated values to an array.
+func splitListNode(node *treeNode, list string) *treeNode {
+	arr := &treeNode{kind: treeArray, line: node.line, column: node.column}
+	if strings.TrimSpace(list) == "" {
+		return arr
+	}
+	for _, el := range strings.Split(list, ",") {
+		el = strings.TrimSpace(el)
+		arr.elements = append(arr.elements, &treeNode{
+			kind: treeScalar, value: el, raw: el, line: node.line, column: node.column,
+		})
+	}
+	return arr
+}
+
+func (tb *treeBinder) BindArrayValueTo(length *int, element *func(int) LazySingleValueBinder) Binding {
+	return tb.newBinding(func() error {
+		arrLength, getElement, err := tb.arrayElements()
+		if err != nil {
+			return err
+		}
+
+		*length = arrLength
+		*element = func(elNum int) LazySingleValueBinder {
+			return getElement(elNum)
+		}
+		return nil
+	})
+}
+
+func (tb *treeBinder) BindObjectArrayValueTo(length *int, element *func(int) ObjectValueBinder) Binding {
+	return tb.newBinding(func() error {
+		arrLength, getElement, err := tb.arrayElements()
+		if err != nil {
+			return err
+		}
+
+		*length = arrLength
+		*element = func(elNum int) ObjectValueBinder {
+			return getElement(elNum)
+		}
+		return nil
+	})
+}
+
+func (tb *treeBinder) BindMapValueTo(keys *[]string, element *func(string) LazySingleValueBinder) Binding {
+	return tb.newBinding(func() error {
+		node, err := tb.lookup()
+		if err != nil {
+			return err
+		}
+		if node.kind != treeObject {
+			return tb.valueError(node, "expected an object, got %s", node.describe())
+		}
+
+		node.used = true
+		*keys = append([]string(nil), node.keys...)
+		*element = func(key string) LazySingleValueBinder {
+			return &treeBinder{
+				doc:  tb.doc,
+				name: tb.name + "." + key,
+				lookup: func() (*treeNode, error) {
+					return tb.doc.lookupProperty(node, tb.name+"."+key, key)
+				},
+			}
+		}
+		return nil
+	})
+}
+
+type treeBinding struct {
+	binder *treeBinder
+	apply  func() error
+	origin Source // set after the binding is successfully applied
+}
+
+var _ interface {
+	Binding
+	BindingFromSource
+} = &treeBinding{}
+
+func (tb *treeBinding) Apply() error {
+	return tb.apply()
+}
+
+// Source returns the document source or, for documents that were merged from
+// multiple files, the source of the file the bound value came from, once the
+// binding has been applied.
+func (tb *treeBinding) Source() Source {
+	if tb.origin != nil {
+		return tb.origin
+	}
+	return tb.binder.doc.source
+}
+
+func (tb *treeBinding) BoundName() string {
+	return tb.binder.name
+}
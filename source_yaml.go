@@ -0,0 +1,763 @@
+package croconf
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SourceYAML is a config source for YAML documents. To keep croconf free of
+// dependencies, it has its own parser for the subset of YAML that is commonly
+// used in config files: block and flow mappings and sequences, plain, quoted
+// and block (| and >) scalars, and comments. Anchors, aliases, tags, complex
+// keys and multiple documents are not supported.
+type SourceYAML struct {
+	doc  *treeDocument
+	init func() error
+}
+
+type YAMLSourceOption func(*SourceYAML)
+
+// WithYAMLFileName sets the file name that is used in the parse and value
+// error messages, instead of the generic "yaml" source name.
+func WithYAMLFileName(fileName string) YAMLSourceOption {
+	return func(sy *SourceYAML) {
+		sy.doc.fileName = fileName
+	}
+}
+
+func NewYAMLSource(data []byte, options ...YAMLSourceOption) *SourceYAML {
+	sy := &SourceYAML{}
+	sy.doc = &treeDocument{source: sy}
+	for _, opt := range options {
+		opt(sy)
+	}
+
+	sy.init = func() error {
+		return sy.doc.init(func() (*treeNode, error) {
+			return parseYAML(string(data))
+		})
+	}
+	return sy
+}
+
+func (sy *SourceYAML) Initialize() error {
+	return sy.init()
+}
+
+func (sy *SourceYAML) GetName() string {
+	return "yaml"
+}
+
+func (sy *SourceYAML) From(name string) *treeBinder {
+	return sy.doc.from(name)
+}
+
+func (sy *SourceYAML) FromPath(path ...string) DocumentValueBinder {
+	return sy.doc.fromPath(path)
+}
+
+// UnknownValues returns errors for all keys in the document that weren't used
+// by any of the fields.
+func (sy *SourceYAML) UnknownValues() []error {
+	return sy.doc.UnknownValues()
+}
+
+type yamlLine struct {
+	num    int    // 1-based line number
+	indent int    // number of leading spaces
+	text   string // the content without the indentation, comments and trailing spaces
+	raw    string // the original line, used for block scalars
+}
+
+type yamlParser struct {
+	lines []*yamlLine
+	pos   int
+}
+
+func parseYAML(data string) (*treeNode, error) {
+	p, err := newYAMLParser(data)
+	if err != nil {
+		return nil, err
+	}
+	if !p.skipBlank() {
+		return newTreeObject(1, 1), nil // empty document
+	}
+
+	first := p.lines[p.pos]
+	root, err := p.parseBlock(first.indent)
+	if err != nil {
+		return nil, err
+	}
+	if root.kind != treeObject {
+		return nil, p.errorf(
+			first, first.indent+1, "expected a mapping at the top level of the document, got %s", root.describe(),
+		)
+	}
+	if p.skipBlank() {
+		line := p.lines[p.pos]
+		return nil, p.errorf(line, line.indent+1, "unexpected indentation")
+	}
+	return root, nil
+}
+
+func newYAMLParser(data string) (*yamlParser, error) {
+	p := &yamlParser{}
+	hasContent, hasDocStart := false, false
+	for i, raw := range strings.Split(strings.TrimSuffix(data, "\n"), "\n") {
+		raw = strings.TrimSuffix(raw, "\r")
+		line := &yamlLine{num: i + 1, raw: raw}
+		p.lines = append(p.lines, line)
+
+		content := strings.TrimLeft(raw, " ")
+		line.indent = len(raw) - len(content)
+		line.text = strings.TrimRight(stripYAMLComment(content), " \t")
+		if line.text == "" {
+			continue
+		}
+
+		if line.indent == 0 {
+			switch {
+			case line.text == "...":
+				line.text = ""
+				p.lines = p.lines[:len(p.lines)-1]
+				return p, nil // the end of the document, the rest is ignored
+			case line.text == "---" || strings.HasPrefix(line.text, "--- "):
+				if hasContent || hasDocStart {
+					return nil, p.errorf(line, 1, "multiple documents are not supported")
+				}
+				if line.text != "---" {
+					return nil, p.errorf(line, 5, "content on the document start line is not supported")
+				}
+				hasDocStart = true
+				line.text = ""
+				continue
+			case line.text[0] == '%' && !hasContent:
+				line.text = "" // directives like %YAML 1.2 can be safely ignored
+				continue
+			}
+		}
+		if line.text[0] == '\t' {
+			return nil, p.errorf(line, line.indent+1, "tabs are not allowed for indentation")
+		}
+		hasContent = true
+	}
+	return p, nil
+}
+
+// stripYAMLComment removes the # comment from the given line, if there is one.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote == '\'' && c == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" [{,", s[i-1]) >= 0):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func (p *yamlParser) errorf(line *yamlLine, column int, format string, args ...interface{}) error {
+	return &treeSyntaxError{line: line.num, column: column, msg: fmt.Sprintf(format, args...)}
+}
+
+// skipBlank moves the position to the next line with some content and returns
+// false if there are no such lines left.
+func (p *yamlParser) skipBlank() bool {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+	return p.pos < len(p.lines)
+}
+
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLMappingKey checks if the text is a "key: value" mapping entry and,
+// if it is, returns the key and the offset of the value.
+func splitYAMLMappingKey(text string) (key string, valueStart int, ok bool) {
+	if text == "" || strings.IndexByte("[{&*!|>%@`?", text[0]) >= 0 || isYAMLSequenceItem(text) {
+		return "", 0, false
+	}
+
+	keyEnd := 0
+	switch text[0] {
+	case '"', '\'':
+		var err error
+		key, keyEnd, err = parseYAMLQuoted(text)
+		if err != nil {
+			return "", 0, false
+		}
+		for keyEnd < len(text) && text[keyEnd] == ' ' {
+			keyEnd++
+		}
+		if keyEnd >= len(text) || text[keyEnd] != ':' || (keyEnd+1 < len(text) && text[keyEnd+1] != ' ') {
+			return "", 0, false
+		}
+	default:
+		keyEnd = strings.Index(text, ": ")
+		if keyEnd < 0 {
+			if !strings.HasSuffix(text, ":") {
+				return "", 0, false
+			}
+			keyEnd = len(text) - 1
+		}
+		key = strings.TrimRight(text[:keyEnd], " ")
+	}
+
+	valueStart = keyEnd + 1
+	for valueStart < len(text) && text[valueStart] == ' ' {
+		valueStart++
+	}
+	return key, valueStart, true
+}
+
+// parseBlock parses the block node that starts on the current line.
+func (p *yamlParser) parseBlock(indent int) (*treeNode, error) {
+	line := p.lines[p.pos]
+	if isYAMLSequenceItem(line.text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitYAMLMappingKey(line.text); ok {
+		return p.parseMapping(indent)
+	}
+	p.pos++
+	return p.parseValue(line.text, line, line.indent+1, indent-1, false)
+}
+
+func (p *yamlParser) parseMapping(indent int) (*treeNode, error) {
+	node := newTreeObject(p.lines[p.pos].num, indent+1)
+	for p.skipBlank() {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, line.indent+1, "unexpected indentation")
+		}
+		key, valueStart, ok := splitYAMLMappingKey(line.text)
+		if !ok {
+			if isYAMLSequenceItem(line.text) {
+				return nil, p.errorf(line, line.indent+1, "unexpected sequence item in a mapping")
+			}
+			return nil, p.errorf(line, line.indent+1, "expected a 'key: value' mapping entry")
+		}
+		if _, exists := node.props[key]; exists {
+			return nil, p.errorf(line, line.indent+1, "duplicate key %q", key)
+		}
+		p.pos++
+
+		value, err := p.parseValue(line.text[valueStart:], line, line.indent+valueStart+1, indent, true)
+		if err != nil {
+			return nil, err
+		}
+		node.set(key, value)
+	}
+	return node, nil
+}
+
+func (p *yamlParser) parseSequence(indent int) (*treeNode, error) {
+	node := &treeNode{kind: treeArray, line: p.lines[p.pos].num, column: indent + 1}
+	for p.skipBlank() {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, line.indent+1, "unexpected indentation")
+		}
+		if !isYAMLSequenceItem(line.text) {
+			break
+		}
+
+		rest := strings.TrimLeft(line.text[1:], " ")
+		itemIndent := line.indent + len(line.text) - len(rest)
+
+		var element *treeNode
+		var err error
+		if _, _, isMapping := splitYAMLMappingKey(rest); isMapping || isYAMLSequenceItem(rest) {
+			// A compact nested collection, e.g. "- key: value" or "- - value",
+			// treat the rest of the line as if it was on a line of its own.
+			line.indent, line.text = itemIndent, rest
+			element, err = p.parseBlock(itemIndent)
+		} else {
+			p.pos++
+			element, err = p.parseValue(rest, line, itemIndent+1, indent, false)
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.elements = append(node.elements, element)
+	}
+	return node, nil
+}
+
+// parseValue parses the value that starts at the given column of a line that
+// has already been consumed, e.g. the "value" part of "key: value" or the
+// nested block on the following lines if it's empty.
+func (p *yamlParser) parseValue(
+	text string, line *yamlLine, column, parentIndent int, inMapping bool,
+) (*treeNode, error) {
+	if text == "" {
+		if p.skipBlank() {
+			next := p.lines[p.pos]
+			if next.indent > parentIndent {
+				return p.parseBlock(next.indent)
+			}
+			// YAML allows sequences in mappings to not be indented
+			if inMapping && next.indent == parentIndent && isYAMLSequenceItem(next.text) {
+				return p.parseSequence(next.indent)
+			}
+		}
+		return &treeNode{kind: treeNull, line: line.num, column: column}, nil
+	}
+
+	switch text[0] {
+	case '&', '*':
+		return nil, p.errorf(line, column, "anchors and aliases are not supported")
+	case '!':
+		return nil, p.errorf(line, column, "tags are not supported")
+	case '@', '`':
+		return nil, p.errorf(line, column, "plain values can't start with the reserved character %q", text[0])
+	case '|', '>':
+		return p.parseBlockScalar(text, line, column, parentIndent)
+	case '[', '{':
+		return p.parseFlow(text, line, column)
+	case '"', '\'':
+		val, end, err := parseYAMLQuoted(text)
+		if err != nil {
+			return nil, p.errorf(line, column+end, "%s", err)
+		}
+		if end < len(text) {
+			return nil, p.errorf(line, column+end, "unexpected content after the quoted value")
+		}
+		return &treeNode{kind: treeScalar, value: val, raw: val, line: line.num, column: column}, nil
+	}
+
+	if strings.Contains(text, ": ") || strings.HasSuffix(text, ":") {
+		return nil, p.errorf(line, column, "mapping values are not allowed in this context")
+	}
+	return newYAMLScalar(text, line.num, column), nil
+}
+
+func (p *yamlParser) parseBlockScalar(header string, line *yamlLine, column, parentIndent int) (*treeNode, error) {
+	folded := header[0] == '>'
+	chomping, contentIndent := byte(0), 0
+	for i := 1; i < len(header); i++ {
+		switch c := header[i]; {
+		case (c == '-' || c == '+') && chomping == 0:
+			chomping = c
+		case c >= '1' && c <= '9' && contentIndent == 0:
+			contentIndent = parentIndent + int(c-'0')
+			if parentIndent < 0 {
+				contentIndent++
+			}
+		default:
+			return nil, p.errorf(line, column+i, "invalid block scalar header %q", header)
+		}
+	}
+
+	var lines []string
+	for ; p.pos < len(p.lines); p.pos++ {
+		raw := p.lines[p.pos].raw
+		content := strings.TrimLeft(raw, " ")
+		if strings.TrimSpace(content) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		indent := len(raw) - len(content)
+		if contentIndent == 0 {
+			if indent <= parentIndent {
+				break
+			}
+			contentIndent = indent
+		}
+		if indent < contentIndent {
+			break
+		}
+		lines = append(lines, raw[contentIndent:])
+	}
+
+	val := joinYAMLBlockLines(lines, folded, chomping)
+	return &treeNode{kind: treeScalar, value: val, raw: val, line: line.num, column: column}, nil
+}
+
+// joinYAMLBlockLines joins the lines of a literal or folded block scalar and
+// applies the chomping indicator to the trailing new lines.
+func joinYAMLBlockLines(lines []string, folded bool, chomping byte) string {
+	trailing := 0
+	for trailing < len(lines) && lines[len(lines)-1-trailing] == "" {
+		trailing++
+	}
+	body := lines[:len(lines)-trailing]
+	isMoreIndented := func(s string) bool { return strings.HasPrefix(s, " ") || strings.HasPrefix(s, "\t") }
+
+	var sb strings.Builder
+	for i, l := range body {
+		sb.WriteString(l)
+		if i == len(body)-1 {
+			break
+		}
+		next := body[i+1]
+		switch {
+		case !folded:
+			sb.WriteByte('\n')
+		case l != "" && next == "" && !isMoreIndented(l):
+			// the line break is folded, only the empty lines are preserved
+		case l != "" && next != "" && !isMoreIndented(l) && !isMoreIndented(next):
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte('\n')
+		}
+	}
+	switch {
+	case chomping == '+':
+		if len(body) > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(strings.Repeat("\n", trailing))
+	case chomping == 0 && len(body) > 0:
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// parseFlow parses flow collections like [1, 2, 3] and {a: 1, b: 2}, which can
+// span multiple lines.
+func (p *yamlParser) parseFlow(text string, line *yamlLine, column int) (*treeNode, error) {
+	fp := &yamlFlowParser{parser: p}
+	fp.addSegment(text, line, column)
+	for depth := yamlFlowDepth(text); depth > 0; {
+		if !p.skipBlank() {
+			return nil, p.errorf(line, column, "unterminated flow collection")
+		}
+		next := p.lines[p.pos]
+		p.pos++
+		fp.addSegment(next.text, next, next.indent+1)
+		depth += yamlFlowDepth(next.text)
+	}
+
+	node, err := fp.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	fp.skipSpaces()
+	if fp.i < len(fp.s) {
+		return nil, fp.errorf("unexpected content after the flow collection")
+	}
+	return node, nil
+}
+
+// yamlFlowDepth returns how many flow collections are opened and not closed in
+// the given text.
+func yamlFlowDepth(text string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+type yamlFlowSegment struct {
+	offset int
+	line   *yamlLine
+	column int
+}
+
+type yamlFlowParser struct {
+	parser   *yamlParser
+	s        string
+	i        int
+	segments []yamlFlowSegment
+}
+
+func (fp *yamlFlowParser) addSegment(text string, line *yamlLine, column int) {
+	if fp.s != "" {
+		fp.s += " "
+	}
+	fp.segments = append(fp.segments, yamlFlowSegment{offset: len(fp.s), line: line, column: column})
+	fp.s += text
+}
+
+func (fp *yamlFlowParser) position(offset int) (*yamlLine, int) {
+	seg := fp.segments[0]
+	for _, s := range fp.segments[1:] {
+		if s.offset > offset {
+			break
+		}
+		seg = s
+	}
+	return seg.line, seg.column + offset - seg.offset
+}
+
+func (fp *yamlFlowParser) errorf(format string, args ...interface{}) error {
+	line, column := fp.position(fp.i)
+	return fp.parser.errorf(line, column, format, args...)
+}
+
+func (fp *yamlFlowParser) skipSpaces() {
+	for fp.i < len(fp.s) && fp.s[fp.i] == ' ' {
+		fp.i++
+	}
+}
+
+func (fp *yamlFlowParser) parseValue() (*treeNode, error) {
+	fp.skipSpaces()
+	if fp.i >= len(fp.s) {
+		return nil, fp.errorf("expected a value")
+	}
+	line, column := fp.position(fp.i)
+
+	switch c := fp.s[fp.i]; c {
+	case '[':
+		return fp.parseSequence(line, column)
+	case '{':
+		return fp.parseMapping(line, column)
+	case '"', '\'':
+		val, end, err := parseYAMLQuoted(fp.s[fp.i:])
+		if err != nil {
+			fp.i += end
+			return nil, fp.errorf("%s", err)
+		}
+		fp.i += end
+		return &treeNode{kind: treeScalar, value: val, raw: val, line: line.num, column: column}, nil
+	case '&', '*':
+		return nil, fp.errorf("anchors and aliases are not supported")
+	case '!':
+		return nil, fp.errorf("tags are not supported")
+	case ',', ']', '}':
+		return nil, fp.errorf("expected a value, got %q", c)
+	}
+
+	start := fp.i
+	for fp.i < len(fp.s) && strings.IndexByte(",]}", fp.s[fp.i]) < 0 && !fp.isValueIndicator() {
+		fp.i++
+	}
+	return newYAMLScalar(strings.TrimRight(fp.s[start:fp.i], " "), line.num, column), nil
+}
+
+// isValueIndicator checks if the current character is a ':' that separates a
+// key from its value.
+func (fp *yamlFlowParser) isValueIndicator() bool {
+	return fp.s[fp.i] == ':' && (fp.i+1 == len(fp.s) || strings.IndexByte(" ,]}", fp.s[fp.i+1]) >= 0)
+}
+
+func (fp *yamlFlowParser) parseSequence(line *yamlLine, column int) (*treeNode, error) {
+	node := &treeNode{kind: treeArray, line: line.num, column: column}
+	fp.i++ // skip '['
+	for {
+		fp.skipSpaces()
+		if fp.i < len(fp.s) && fp.s[fp.i] == ']' {
+			fp.i++
+			return node, nil
+		}
+		element, err := fp.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		node.elements = append(node.elements, element)
+
+		fp.skipSpaces()
+		switch {
+		case fp.i < len(fp.s) && fp.s[fp.i] == ',':
+			fp.i++
+		case fp.i < len(fp.s) && fp.s[fp.i] == ']':
+			fp.i++
+			return node, nil
+		case fp.i < len(fp.s) && fp.s[fp.i] == ':':
+			return nil, fp.errorf("mappings in flow sequences are not supported")
+		default:
+			return nil, fp.errorf("expected ',' or ']' in the flow sequence")
+		}
+	}
+}
+
+func (fp *yamlFlowParser) parseMapping(line *yamlLine, column int) (*treeNode, error) {
+	node := newTreeObject(line.num, column)
+	fp.i++ // skip '{'
+	for {
+		fp.skipSpaces()
+		if fp.i < len(fp.s) && fp.s[fp.i] == '}' {
+			fp.i++
+			return node, nil
+		}
+
+		keyStart := fp.i
+		keyNode, err := fp.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		if keyNode.kind == treeArray || keyNode.kind == treeObject {
+			fp.i = keyStart
+			return nil, fp.errorf("complex mapping keys are not supported")
+		}
+		key := keyNode.raw
+		if s, ok := keyNode.value.(string); ok {
+			key = s
+		}
+		if _, exists := node.props[key]; exists {
+			fp.i = keyStart
+			return nil, fp.errorf("duplicate key %q", key)
+		}
+
+		fp.skipSpaces()
+		value := &treeNode{kind: treeNull, line: keyNode.line, column: keyNode.column}
+		if fp.i < len(fp.s) && fp.s[fp.i] == ':' {
+			fp.i++
+			fp.skipSpaces()
+			if fp.i < len(fp.s) && strings.IndexByte(",}", fp.s[fp.i]) < 0 {
+				if value, err = fp.parseValue(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		node.set(key, value)
+
+		fp.skipSpaces()
+		switch {
+		case fp.i < len(fp.s) && fp.s[fp.i] == ',':
+			fp.i++
+		case fp.i < len(fp.s) && fp.s[fp.i] == '}':
+			fp.i++
+			return node, nil
+		default:
+			return nil, fp.errorf("expected ',' or '}' in the flow mapping")
+		}
+	}
+}
+
+// parseYAMLQuoted parses the single or double-quoted string at the start of
+// the given text and returns its value and the offset right after it.
+func parseYAMLQuoted(text string) (string, int, error) {
+	quote := text[0]
+	var sb strings.Builder
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == quote && quote == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			sb.WriteByte('\'')
+			i++
+		case c == quote:
+			return sb.String(), i + 1, nil
+		case c == '\\' && quote == '"':
+			n, err := writeYAMLEscape(&sb, text[i+1:])
+			if err != nil {
+				return "", i, err
+			}
+			i += n
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", len(text), fmt.Errorf("unterminated quoted string")
+}
+
+// writeYAMLEscape decodes the escape sequence at the start of the text (right
+// after the backslash) and returns how many bytes it consumed.
+func writeYAMLEscape(sb *strings.Builder, text string) (int, error) {
+	if text == "" {
+		return 0, fmt.Errorf("unterminated escape sequence")
+	}
+	simple := map[byte]string{
+		'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v", 'f': "\f",
+		'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+		'N': "\u0085", '_': "\u00a0", 'L': "\u2028", 'P': "\u2029",
+	}
+	if s, ok := simple[text[0]]; ok {
+		sb.WriteString(s)
+		return 1, nil
+	}
+
+	digits := map[byte]int{'x': 2, 'u': 4, 'U': 8}[text[0]]
+	if digits == 0 || len(text) < digits+1 {
+		return 0, fmt.Errorf("invalid escape sequence '\\%c'", text[0])
+	}
+	code, err := strconv.ParseUint(text[1:digits+1], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(code)) {
+		return 0, fmt.Errorf("invalid escape sequence '\\%s'", text[:digits+1])
+	}
+	sb.WriteRune(rune(code))
+	return digits + 1, nil
+}
+
+var (
+	yamlIntRegexp   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlFloatRegexp = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// newYAMLScalar resolves the type of plain scalars according to the YAML 1.2
+// core schema.
+func newYAMLScalar(text string, line, column int) *treeNode {
+	node := &treeNode{kind: treeScalar, value: text, raw: text, line: line, column: column}
+
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		node.kind, node.value = treeNull, nil
+		return node
+	case "true", "True", "TRUE":
+		node.value = true
+		return node
+	case "false", "False", "FALSE":
+		node.value = false
+		return node
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		node.value = math.Inf(1)
+		return node
+	case "-.inf", "-.Inf", "-.INF":
+		node.value = math.Inf(-1)
+		return node
+	case ".nan", ".NaN", ".NAN":
+		node.value = math.NaN()
+		return node
+	}
+
+	switch {
+	case yamlIntRegexp.MatchString(text):
+		if val, err := strconv.ParseInt(text, 10, 64); err == nil {
+			node.value = val
+		} else if val, err := strconv.ParseUint(strings.TrimPrefix(text, "+"), 10, 64); err == nil {
+			node.value = val
+		}
+	case strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0o"):
+		base := map[string]int{"0x": 16, "0o": 8}[text[:2]]
+		if val, err := strconv.ParseUint(text[2:], base, 64); err == nil {
+			node.value = val
+			if val <= math.MaxInt64 {
+				node.value = int64(val)
+			}
+		}
+	case yamlFloatRegexp.MatchString(text):
+		if val, err := strconv.ParseFloat(text, 64); err == nil {
+			node.value = val
+		}
+	}
+	return node
+}
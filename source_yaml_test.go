@@ -0,0 +1,336 @@
+package croconf
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testYAMLConfig = `
+# comments should be ignored
+vus: 10
+duration: 1m30s
+pi: 3.14
+throw: true
+userAgent: "croconf \"test\"" # a trailing comment
+name: 'it''s a test'
+hosts: [example.com, 'k6.io']
+dns:
+  server: 8.8.8.8
+  ttl: 5m
+tags:
+  foo: bar
+  baz: 1
+ports:
+- 80
+- 443
+targets:
+  - url: https://k6.io
+    maxRetries: 3
+    tags: [a, b]
+  - url: https://example.com
+description: |
+  first line
+  second line
+folded: >-
+  one
+  two
+empty:
+`
+
+func TestYAMLBindSingleValues(t *testing.T) {
+	t.Parallel()
+
+	source := NewYAMLSource([]byte(testYAMLConfig))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var vus, ttl int64
+	var duration time.Duration
+	var pi float64
+	var throw bool
+	var userAgent, name, description, folded, server string
+	var serverIP net.IP
+
+	bindings := []Binding{
+		source.From("vus").BindIntValueTo(&vus),
+		source.From("duration").BindDurationValueTo(&duration, time.Millisecond),
+		source.From("pi").BindFloatValueTo(&pi),
+		source.From("throw").BindBoolValueTo(&throw),
+		source.From("userAgent").BindStringValueTo(&userAgent),
+		source.From("name").BindStringValueTo(&name),
+		source.From("description").BindStringValueTo(&description),
+		source.From("folded").BindStringValueTo(&folded),
+		source.From("dns").From("server").BindStringValueTo(&server),
+		source.From("dns").From("server").BindTextBasedValueTo(&serverIP),
+	}
+	for _, b := range bindings {
+		if err := b.Apply(); err != nil {
+			t.Fatalf("unexpected error for %s: %s", b.(BindingFromSource).BoundName(), err)
+		}
+	}
+
+	if vus != 10 || duration != 90*time.Second || pi != 3.14 || !throw {
+		t.Errorf("unexpected values vus=%d duration=%s pi=%g throw=%t", vus, duration, pi, throw)
+	}
+	if userAgent != `croconf "test"` || name != "it's a test" {
+		t.Errorf("unexpected quoted values %q and %q", userAgent, name)
+	}
+	if description != "first line\nsecond line\n" || folded != "one two" {
+		t.Errorf("unexpected block values %q and %q", description, folded)
+	}
+	if server != "8.8.8.8" || !serverIP.Equal(net.IPv4(8, 8, 8, 8)) {
+		t.Errorf("unexpected dns server %q (%s)", server, serverIP)
+	}
+
+	var ttlDuration time.Duration
+	if err := source.From("dns").From("ttl").BindDurationValueTo(&ttlDuration, time.Second).Apply(); err != nil {
+		t.Errorf("unexpected error %s", err)
+	}
+	if ttlDuration != 5*time.Minute {
+		t.Errorf("unexpected ttl %s", ttlDuration)
+	}
+
+	binding := source.From("dns").From("ttl").BindIntValueTo(&ttl)
+	if bfs := binding.(BindingFromSource); bfs.BoundName() != "dns.ttl" || bfs.Source() != source {
+		t.Errorf("unexpected bound name %s or source", bfs.BoundName())
+	}
+	expErr := `yaml:12:8: dns.ttl: expected an integer, got "5m"`
+	if err := binding.Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	for _, missing := range []string{"missing", "empty"} {
+		err := source.From(missing).BindIntValueTo(&vus).Apply()
+		if err == nil || err.Error() != "field "+missing+" is missing in config source yaml" {
+			t.Errorf("expected missing field error for %s, got '%s'", missing, err)
+		}
+	}
+}
+
+func TestYAMLBindCollections(t *testing.T) {
+	t.Parallel()
+
+	source := NewYAMLSource([]byte(testYAMLConfig), WithYAMLFileName("config.yaml"))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+
+	var hosts []string
+	var ports []uint16
+	var tags map[string]string
+	var targets []testTarget
+
+	cm := NewManager()
+	cm.AddField(NewStringSliceField(&hosts, source.From("hosts")))
+	cm.AddField(NewUint16SliceField(&ports, source.From("ports")))
+	cm.AddField(NewStringMapField(&tags, source.From("tags")))
+	cm.AddField(NewStructSliceField(&targets, func(el *testTarget, binder ObjectValueBinder, cm *Manager) {
+		cm.AddField(NewStringField(&el.URL, binder.Property("url")))
+		cm.AddField(NewInt64Field(&el.MaxRetries, binder.Property("maxRetries")))
+		cm.AddField(NewStringSliceField(&el.Tags, binder.Property("tags")))
+	}, source.From("targets")))
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+
+	if exp := []string{"example.com", "k6.io"}; !reflect.DeepEqual(hosts, exp) {
+		t.Errorf("expected hosts %v, got %v", exp, hosts)
+	}
+	if exp := []uint16{80, 443}; !reflect.DeepEqual(ports, exp) {
+		t.Errorf("expected ports %v, got %v", exp, ports)
+	}
+	if exp := map[string]string{"foo": "bar", "baz": "1"}; !reflect.DeepEqual(tags, exp) {
+		t.Errorf("expected tags %v, got %v", exp, tags)
+	}
+	expTargets := []testTarget{
+		{URL: "https://k6.io", MaxRetries: 3, Tags: []string{"a", "b"}},
+		{URL: "https://example.com"},
+	}
+	if !reflect.DeepEqual(targets, expTargets) {
+		t.Errorf("expected targets %v, got %v", expTargets, targets)
+	}
+
+	var vus []int64
+	expErr := `config.yaml:3:6: vus: expected an array, got 10`
+	if err := NewInt64SliceField(&vus, source.From("vus")).Bindings()[0].Apply(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+}
+
+func TestYAMLUnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	yaml := []byte(`vsu: 10
+dns:
+  tll: 5
+  server: 8.8.8.8
+  foo: bar
+dsn: {ttl: 1}
+tags: {a: b}
+hosts: example.com
+targets:
+  - url: a
+  - url: b
+    maxRetires: 1
+`)
+
+	newManager := func(options ...ManagerOption) *Manager {
+		source := NewYAMLSource(yaml, WithYAMLFileName("config.yaml"))
+		var vus, ttl int64
+		var server string
+		var tags map[string]string
+		var targets []testTarget
+
+		cm := NewManager(options...)
+		cm.AddField(NewInt64Field(&vus, source.From("vus")))
+		cm.AddField(NewInt64Field(&ttl, source.From("dns").From("ttl")))
+		cm.AddField(NewStringField(&server, source.From("dns").From("server")))
+		cm.AddField(NewStringMapField(&tags, source.From("tags")))
+		cm.AddField(NewStructSliceField(
+			&targets,
+			func(target *testTarget, el ObjectValueBinder, cm *Manager) {
+				cm.AddField(NewStringField(&target.URL, el.Property("url")))
+				cm.AddField(NewInt64Field(&target.MaxRetries, el.Property("maxRetries")))
+			},
+			source.From("targets"),
+		))
+		return cm
+	}
+
+	expWarnings := []string{
+		"config.yaml:1:6: unknown key vsu, did you mean vus?",
+		"config.yaml:3:8: unknown key dns.tll, did you mean dns.ttl?",
+		"config.yaml:5:8: unknown key dns.foo",
+		"config.yaml:6:6: unknown key dsn, did you mean dns?",
+		"config.yaml:8:8: unknown key hosts",
+		"config.yaml:12:17: unknown key targets[1].maxRetires, did you mean targets[1].maxRetries?",
+	}
+
+	cm := newManager()
+	if err := cm.Consolidate(); err != nil {
+		t.Fatalf("unexpected consolidation error %s", err)
+	}
+	warnings := make([]string, 0, len(cm.Warnings()))
+	for _, w := range cm.Warnings() {
+		warnings = append(warnings, w.Error())
+	}
+	if !reflect.DeepEqual(warnings, expWarnings) {
+		t.Errorf("expected warnings %q, got %q", expWarnings, warnings)
+	}
+
+	cm = newManager(WithStrictUnknownValues())
+	expErr := "Unknown config values: \n\t- " + strings.Join(expWarnings, "\n\t- ")
+	if err := cm.Consolidate(); err == nil || err.Error() != expErr {
+		t.Errorf("expected error '%s', got '%s'", expErr, err)
+	}
+
+	// values that fail to bind aren't marked as used
+	source := NewYAMLSource([]byte("vus: ten\nduration: 1m"))
+	if err := source.Initialize(); err != nil {
+		t.Fatalf("received an unexpected init error %s", err)
+	}
+	var vus int64
+	if err := source.From("vus").BindIntValueTo(&vus).Apply(); err == nil {
+		t.Errorf("expected an error when binding vus")
+	}
+	unknown := source.UnknownValues()
+	if len(unknown) != 2 || !strings.HasPrefix(unknown[0].Error(), "yaml:1:6: unknown key vus") {
+		t.Errorf("unexpected unknown values %q", unknown)
+	}
+}
+
+func TestYAMLParseErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		yaml   string
+		expErr string
+	}{
+		{"a: 1\n  b: 2", "yaml:2:3: unexpected indentation"},
+		{"a: 1\na: 2", `yaml:2:1: duplicate key "a"`},
+		{"a: [1, 2}", "yaml:1:9: expected ',' or ']' in the flow sequence"},
+		{"a: [1, 2", "yaml:1:4: unterminated flow collection"},
+		{"a: \"foo", "yaml:1:8: unterminated quoted string"},
+		{"a: *ref", "yaml:1:4: anchors and aliases are not supported"},
+		{"- 1\n- 2", "yaml:1:1: expected a mapping at the top level of the document, got an array"},
+		{"a: b: c", "yaml:1:4: mapping values are not allowed in this context"},
+		{"a:\n  - 1\n  b: 2", "yaml:3:3: unexpected indentation"},
+		{"a: 1\n---\nb: 2", "yaml:2:1: multiple documents are not supported"},
+		{"a:\n\t- 1", "yaml:2:1: tabs are not allowed for indentation"},
+		{"a: {b: 1, b: 2}", `yaml:1:11: duplicate key "b"`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.yaml, func(t *testing.T) {
+			t.Parallel()
+			err := NewYAMLSource([]byte(tc.yaml)).Initialize()
+			if err == nil || err.Error() != tc.expErr {
+				t.Errorf("expected error '%s', got '%s'", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestYAMLParseValues(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		yaml string
+		exp  interface{}
+	}{
+		{"v: 0x1F", int64(31)},
+		{"v: 0o17", int64(15)},
+		{"v: -12", int64(-12)},
+		{"v: 18446744073709551615", uint64(18446744073709551615)},
+		{"v: 1e3", float64(1000)},
+		{"v: True", true},
+		{"v: '10'", "10"},
+		{"v: \"\\u00e9\\t\"", "é\t"},
+		{"v: http://example.com/#anchor", "http://example.com/#anchor"},
+		{"v: foo # bar", "foo"},
+		{"---\nv: |+\n  a\n\n", "a\n\n"},
+		{"v: >\n  a\n  b\n\n  c\n", "a b\nc\n"},
+		{"v:\n  - - 1\n    - 2", []interface{}{[]interface{}{int64(1), int64(2)}}},
+		{"v:\n- a: 1\n  b: {c: [x, 'y z']}", []interface{}{map[string]interface{}{
+			"a": int64(1), "b": map[string]interface{}{"c": []interface{}{"x", "y z"}},
+		}}},
+		{"v: {\n  a: 1,\n  b: 2\n}", map[string]interface{}{"a": int64(1), "b": int64(2)}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.yaml, func(t *testing.T) {
+			t.Parallel()
+			root, err := parseYAML(tc.yaml)
+			if err != nil {
+				t.Fatalf("unexpected error %s", err)
+			}
+			if val := treeNodeToInterface(root.props["v"]); !reflect.DeepEqual(val, tc.exp) {
+				t.Errorf("expected %#v, got %#v", tc.exp, val)
+			}
+		})
+	}
+}
+
+func treeNodeToInterface(node *treeNode) interface{} {
+	switch node.kind {
+	case treeArray:
+		res := make([]interface{}, len(node.elements))
+		for i, el := range node.elements {
+			res[i] = treeNodeToInterface(el)
+		}
+		return res
+	case treeObject:
+		res := make(map[string]interface{}, len(node.props))
+		for k, v := range node.props {
+			res[k] = treeNodeToInterface(v)
+		}
+		return res
+	}
+	return node.value
+}
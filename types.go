@@ -1,6 +1,9 @@
 package croconf
 
-import "encoding"
+import (
+	"encoding"
+	"time"
+)
 
 type Field interface {
 	Destination() interface{}
@@ -12,6 +15,13 @@ type Source interface {
 	GetName() string // TODO: remove?
 }
 
+// SourceWithUnknownValues is implemented by sources that can detect values
+// which weren't used by any field, e.g. mistyped keys in config files.
+type SourceWithUnknownValues interface {
+	Source
+	UnknownValues() []error
+}
+
 type Binding interface {
 	Apply() error
 }
@@ -22,6 +32,14 @@ type BindingFromSource interface {
 	BoundName() string
 }
 
+// BindingWithUsage is implemented by bindings that can be used in more ways
+// than their BoundName() shows, which should be mentioned in help texts, e.g.
+// the --no-<name> negations of boolean CLI flags.
+type BindingWithUsage interface {
+	BindingFromSource
+	Usage() string
+}
+
 type LazySingleValueBinder interface {
 	StringValueBinder
 	IntValueBinder
@@ -55,6 +73,16 @@ type TextBasedValueBinder interface {
 	BindTextBasedValueTo(dest encoding.TextUnmarshaler) Binding
 }
 
+// DurationValueBinder binds time.Duration values. Numbers without any units
+// are ambiguous, so bareNumberUnit specifies how they should be interpreted,
+// e.g. a bareNumberUnit of time.Millisecond means that 1500 is 1.5 seconds.
+// It's not a part of LazySingleValueBinder, so that existing binders don't
+// have to implement it. Use a type assertion to check if a LazySingleValueBinder,
+// e.g. an array element, also supports durations.
+type DurationValueBinder interface {
+	BindDurationValueTo(dest *time.Duration, bareNumberUnit time.Duration) Binding
+}
+
 type CustomValueBinder interface {
 	BindValue() Binding
 }
@@ -63,3 +91,43 @@ type CustomValueBinder interface {
 type ArrayValueBinder interface {
 	BindArrayValueTo(length *int, element *func(int) LazySingleValueBinder) Binding
 }
+
+// MapValueBinder is similar to ArrayValueBinder, but for key-value
+// dictionaries, e.g. map[string]string, where the elements are identified by
+// their keys instead of their positions.
+type MapValueBinder interface {
+	BindMapValueTo(keys *[]string, element *func(key string) LazySingleValueBinder) Binding
+}
+
+// ObjectArrayValueBinder can be used for arrays of objects, e.g. slices of
+// structs, where every element property can have its own bindings.
+type ObjectArrayValueBinder interface {
+	BindObjectArrayValueTo(length *int, element *func(int) ObjectValueBinder) Binding
+}
+
+// ObjectValueBinder is a single object, e.g. an element of an array of objects.
+type ObjectValueBinder interface {
+	Property(name string) PropertyValueBinder
+}
+
+type PropertyValueBinder interface {
+	LazySingleValueBinder
+	ArrayValueBinder
+	MapValueBinder
+}
+
+// NamedValueBinder is a value that can be bound to all of the built-in single
+// value, duration, array and map fields, e.g. an environment variable.
+type NamedValueBinder interface {
+	PropertyValueBinder
+	DurationValueBinder
+}
+
+// DocumentValueBinder is a value in a structured config document, e.g. JSON or
+// YAML. Besides everything a NamedValueBinder supports, it can also be an array
+// of objects or an object with nested values.
+type DocumentValueBinder interface {
+	NamedValueBinder
+	ObjectArrayValueBinder
+	ObjectValueBinder
+}
@@ -1,8 +1,14 @@
 package croconf
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
 )
 
 func checkIntBitsize(val int64, bitSize int) error {
@@ -45,3 +51,168 @@ func parseFloat(s string) (float64, *BindValueError) {
 	}
 	return val, nil
 }
+
+func checkFloatBitsize(val float64, bitSize int) error {
+	if bitSize == 64 || math.IsNaN(val) || math.IsInf(val, 0) {
+		return nil
+	}
+	// See MaxFloat32 and SmallestNonzeroFloat32 in https://golang.org/pkg/math/#pkg-constants
+	if math.Abs(val) > math.MaxFloat32 {
+		return fmt.Errorf("invalid value %g, it must be between %g and %g", val, -math.MaxFloat32, math.MaxFloat32)
+	}
+	if val != 0 && float32(val) == 0 {
+		return fmt.Errorf(
+			"invalid value %g, it is too close to 0 and would be rounded to 0 as a %d-bit float", val, bitSize,
+		)
+	}
+	return nil
+}
+
+// parseKeyValuePairs parses a list of key=value strings, like the ones we get
+// from repeated CLI flags or comma-separated environment variables. The keys
+// are returned in the order they were first seen, later values win.
+func parseKeyValuePairs(name string, pairs []string) ([]string, map[string]string, error) {
+	keys := make([]string, 0, len(pairs))
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		idx := strings.IndexRune(pair, '=')
+		if idx < 1 {
+			return nil, nil, fmt.Errorf("invalid value '%s' for %s, expected a key=value pair", pair, name)
+		}
+		key, value := pair[:idx], pair[idx+1:]
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+	return keys, values, nil
+}
+
+// toScreamingSnakeCase converts camelCase names to SCREAMING_SNAKE_CASE ones,
+// which are the usual format for environment variable names.
+func toScreamingSnakeCase(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+			sb.WriteRune('_')
+		}
+		if r == '-' || r == '.' {
+			r = '_'
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
+
+// parseDuration parses Go duration strings like "1h30m", as well as durations
+// with a days suffix, like "2d" or "1d12h". Numbers without any units are
+// multiplied by bareNumberUnit.
+func parseDuration(s string, bareNumberUnit time.Duration) (time.Duration, *BindValueError) {
+	if val, err := strconv.ParseFloat(s, 64); err == nil {
+		result := val * float64(bareNumberUnit)
+		if math.IsNaN(result) || result >= math.MaxInt64 || result < math.MinInt64 {
+			return 0, NewBindValueError("parseDuration", s, errors.New("value out of range"))
+		}
+		return time.Duration(result), nil
+	}
+
+	dPos := strings.IndexByte(s, 'd')
+	if dPos < 0 {
+		val, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, NewBindValueError("parseDuration", s, errors.New("invalid duration"))
+		}
+		return val, nil
+	}
+
+	days, err := strconv.ParseInt(s[:dPos], 10, 64)
+	if err != nil {
+		return 0, NewBindValueError("parseDuration", s, errors.New("invalid number of days"))
+	}
+	if maxDays := int64(math.MaxInt64 / (24 * time.Hour)); days > maxDays || days < -maxDays {
+		return 0, NewBindValueError("parseDuration", s, errors.New("value out of range"))
+	}
+
+	var hours time.Duration
+	if dPos+1 < len(s) { // case "1d12h"
+		hours, err = time.ParseDuration(s[dPos+1:])
+		if err != nil || hours < 0 {
+			return 0, NewBindValueError("parseDuration", s, errors.New("invalid duration after the days"))
+		}
+	}
+	if days < 0 || strings.HasPrefix(s, "-") {
+		hours = -hours
+	}
+	daysDuration := time.Duration(days) * 24 * time.Hour
+	result := daysDuration + hours
+	if (hours > 0 && result < daysDuration) || (hours < 0 && result > daysDuration) {
+		return 0, NewBindValueError("parseDuration", s, errors.New("value out of range"))
+	}
+	return result, nil
+}
+
+// textPosition returns the 1-based line and column of the given byte offset.
+func textPosition(data []byte, offset int) (line, column int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	lineStart := bytes.LastIndexByte(data[:offset], '\n') + 1
+	return bytes.Count(data[:offset], []byte{'\n'}) + 1, offset - lineStart + 1
+}
+
+// editDistance returns the optimal string alignment distance between a and b,
+// i.e. the Levenshtein distance where swapping two adjacent characters also
+// counts as a single edit.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// we only need the last 3 rows of the full matrix
+	prev2, prev, cur := make([]int, len(rb)+1), make([]int, len(rb)+1), make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = minInt(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
+
+func minInt(first int, rest ...int) int {
+	for _, v := range rest {
+		if v < first {
+			first = v
+		}
+	}
+	return first
+}
+
+// closestName returns the candidate that is the most similar to name, or an
+// empty string if none of them is similar enough to be a likely typo. Ties are
+// resolved in favor of the earlier candidates.
+func closestName(name string, candidates []string) string {
+	// there are no meaningful suggestions for single letters
+	nameLength := len([]rune(name))
+	maxDistance := nameLength / 3
+	if maxDistance < 1 && nameLength > 1 {
+		maxDistance = 1
+	}
+	best, bestDistance := "", maxDistance+1
+	for _, candidate := range candidates {
+		if d := editDistance(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}